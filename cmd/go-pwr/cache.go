@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rocketpowerinc/go-pwr/internal/config"
+	"github.com/rocketpowerinc/go-pwr/internal/git"
+)
+
+// runCache implements the "cache" command.
+func runCache(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr cache <dir|prune> [flags]\n")
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "dir":
+		fmt.Println(cfg.CacheDir)
+		return 0
+	case "prune":
+		fs := flag.NewFlagSet("cache prune", flag.ExitOnError)
+		dryRun := fs.Bool("dry-run", false, "Only list the clones that would be removed")
+		fs.Parse(args[1:])
+
+		pruned, err := git.PruneCache(cfg, *dryRun)
+		for _, path := range pruned {
+			if *dryRun {
+				fmt.Printf("Would remove: %s\n", path)
+			} else {
+				fmt.Printf("Removed: %s\n", path)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning cache: %v\n", err)
+			return 1
+		}
+		if len(pruned) == 0 {
+			fmt.Println("No unused clones found.")
+		}
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown cache command: %s\n", args[0])
+		return 2
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runCommand dispatches a subcommand and returns the process exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "cache":
+		return runCache(args)
+	case "lint":
+		return runLint(args)
+	case "list":
+		return runList(args)
+	case "run":
+		return runRun(args)
+	case "workflow":
+		return runWorkflow(args)
+	case "fav":
+		return runFav(args)
+	case "facts":
+		return runFacts(args)
+	case "doctor":
+		return runDoctor(args)
+	case "help":
+		flag.Usage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+		flag.Usage()
+		return 2
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rocketpowerinc/go-pwr/internal/config"
+	"github.com/rocketpowerinc/go-pwr/internal/git"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// runDoctor implements the "doctor" command, which reports on the tools
+// and caches go-pwr depends on.
+func runDoctor(args []string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if err := platform.RegisterInterpreterCommands(cfg.Interpreters); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid interpreters config: %v\n", err)
+		return 1
+	}
+
+	// Problems fail the command; warnings only limit what go-pwr can do
+	status := 0
+	check := func(ok bool, label, detail string) {
+		mark := "✓"
+		if !ok {
+			mark = "✗"
+			status = 1
+		}
+		fmt.Printf("%s %-14s %s\n", mark, label, detail)
+	}
+	warn := func(ok bool, label, detail string) {
+		mark := "✓"
+		if !ok {
+			mark = "!"
+		}
+		fmt.Printf("%s %-14s %s\n", mark, label, detail)
+	}
+
+	fmt.Printf("  %-14s %s\n", "Repository", cfg.RepoURL)
+	if _, err := os.Stat(cfg.ScriptbinPath); err != nil {
+		check(false, "Scripts", cfg.ScriptbinPath+" is missing (run go-pwr once to sync)")
+	} else {
+		scriptCount := len(scripts.GetAllScriptsRecursively(cfg.ScriptbinPath))
+		check(true, "Scripts", fmt.Sprintf("%s (%d scripts)", cfg.ScriptbinPath, scriptCount))
+	}
+
+	if client, err := git.NewClient(cfg.GitClient); err != nil {
+		check(false, "Git", err.Error())
+	} else {
+		check(true, "Git", client.Name()+" client")
+	}
+
+	bat := ""
+	for _, name := range []string{"bat", "batcat"} {
+		if path, err := exec.LookPath(name); err == nil {
+			bat = path
+			break
+		}
+	}
+	warn(bat != "", "bat", orDefault(bat, "not installed, previews are not highlighted"))
+
+	if platform.IsElevated() {
+		check(true, "Elevation", "running with administrator rights")
+	} else if elevate, err := platform.ElevationCommand(cfg.ElevateCommand); err != nil {
+		warn(false, "Elevation", err.Error())
+	} else {
+		check(true, "Elevation", "admin scripts run via "+elevate)
+	}
+
+	// Interpreters only matter for the scripts that need them
+	seen := make(map[string]bool)
+	var missing []string
+	for _, ext := range platform.SupportedExtensions() {
+		interp, ok := platform.InterpreterFor("script" + ext)
+		if !ok || seen[interp.Name] {
+			continue
+		}
+		seen[interp.Name] = true
+		if !interp.Available() {
+			missing = append(missing, interp.Program())
+		}
+	}
+	warn(len(missing) == 0, "Interpreters", "missing: "+orDefault(strings.Join(missing, ", "), "none"))
+
+	index := scripts.OpenIndex(scripts.IndexPath(cfg.CacheDir))
+	fmt.Printf("  %-14s %s\n", "Cache dir", cfg.CacheDir)
+	fmt.Printf("  %-14s %d scripts in %s\n", "Index", index.Len(), filepath.Base(scripts.IndexPath(cfg.CacheDir)))
+
+	if stats, err := scripts.LoadCacheStats(scripts.CacheStatsPath(cfg.CacheDir)); err == nil {
+		hitRate := 0
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			hitRate = stats.Hits * 100 / lookups
+		}
+		fmt.Printf("  %-14s %d hits, %d misses (%d%% hit rate), %d evictions, %d invalidations\n",
+			"Preview cache", stats.Hits, stats.Misses, hitRate, stats.Evictions, stats.Invalidations)
+		fmt.Printf("  %-14s %d entries, %.1f KiB at the end of the last session\n", "", stats.Entries, float64(stats.Bytes)/1024)
+	} else {
+		fmt.Printf("  %-14s no session recorded yet\n", "Preview cache")
+	}
+	return status
+}
+
+// orDefault returns value, or fallback if value is empty.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// runFacts implements the "facts" command. It prints the host facts in the
+// same GO_PWR_* form scripts receive them.
+func runFacts(args []string) int {
+	for _, variable := range platform.HostFacts().Env() {
+		fmt.Println(variable)
+	}
+	return 0
+}
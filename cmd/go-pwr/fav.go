@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/rocketpowerinc/go-pwr/internal/config"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// runFav implements the "fav" command, which lists the pinned scripts and
+// pins or unpins them.
+func runFav(args []string) int {
+	fs := flag.NewFlagSet("fav", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the pinned scripts and their metadata as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr fav [-json]\n")
+		fmt.Fprintf(os.Stderr, "       go-pwr fav add|rm <script>\n\n")
+		fmt.Fprintf(os.Stderr, "Lists the scripts pinned with 'p' in the Scripts tab, or pins and unpins one.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if err := platform.RegisterInterpreterCommands(cfg.Interpreters); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid interpreters config: %v\n", err)
+		return 1
+	}
+
+	switch fs.Arg(0) {
+	case "":
+	case "add", "rm":
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return 2
+		}
+		return updateFavorites(cfg, fs.Arg(0) == "add", fs.Arg(1))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown fav command: %s\n", fs.Arg(0))
+		return 2
+	}
+
+	items := scripts.Favorites(cfg.ScriptbinPath, cfg.Favorites)
+	if *asJSON {
+		infos := make([]scriptInfo, 0, len(items))
+		for _, item := range items {
+			infos = append(infos, newScriptInfo(item.(scripts.Item)))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(infos); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing scripts: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(cfg.Favorites) == 0 {
+		fmt.Println("No pinned scripts. Press 'p' on a script in the Scripts tab to pin it.")
+		return 0
+	}
+	for _, item := range items {
+		s := item.(scripts.Item)
+		if summary := s.Summary(); summary != "" {
+			fmt.Printf("%s - %s\n", s.Title(), summary)
+		} else {
+			fmt.Println(s.Title())
+		}
+	}
+	if missing := len(cfg.Favorites) - len(items); missing > 0 {
+		fmt.Fprintf(os.Stderr, "%d pinned scripts are no longer in the repository\n", missing)
+	}
+	return 0
+}
+
+// updateFavorites pins or unpins a script of the repository.
+func updateFavorites(cfg *config.Config, pin bool, name string) int {
+	item, err := scripts.FindScript(cfg.ScriptbinPath, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	rel, ok := scripts.RelativePath(cfg.ScriptbinPath, item.Description())
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: %s is not in the repository at %s\n", item.Description(), cfg.ScriptbinPath)
+		return 1
+	}
+
+	favorites := slices.Clone(cfg.Favorites)
+	i := slices.Index(favorites, rel)
+	switch {
+	case pin && i < 0:
+		favorites = append(favorites, rel)
+	case !pin && i >= 0:
+		favorites = slices.Delete(favorites, i, i+1)
+	default:
+		return 0 // Already the way it was asked for
+	}
+	if err := config.SaveFavorites(cfg.RepoURL, favorites); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving favorites: %v\n", err)
+		return 1
+	}
+	return 0
+}
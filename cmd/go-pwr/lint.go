@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rocketpowerinc/go-pwr/internal/lint"
+)
+
+// runLint implements the "lint" command.
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	format := fs.String("format", "text", "Output format: text or json")
+	strict := fs.Bool("strict", false, "Treat warnings as errors")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr lint [-format text|json] [-strict] [path]\n\n")
+		fmt.Fprintf(os.Stderr, "Checks a scriptbin checkout (default: current directory) for tagging and script problems.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	root := "."
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+
+	report, err := lint.Run(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error linting %s: %v\n", root, err)
+		return 2
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			return 2
+		}
+	case "text":
+		for _, issue := range report.Issues {
+			location := issue.Path
+			if issue.Line > 0 {
+				location = fmt.Sprintf("%s:%d", issue.Path, issue.Line)
+			}
+			fmt.Printf("%s: %s: %s [%s]\n", location, issue.Severity, issue.Message, issue.Check)
+		}
+		fmt.Printf("\n%d scripts checked: %d errors, %d warnings\n", report.Scripts, report.Errors, report.Warnings)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format: %s (supported: text, json)\n", *format)
+		return 2
+	}
+
+	if report.HasFailures(*strict) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rocketpowerinc/go-pwr/internal/config"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// scriptInfo is the JSON form of a script in "list -json" output.
+type scriptInfo struct {
+	Name        string              `json:"name"`
+	Path        string              `json:"path"`
+	Description string              `json:"description,omitempty"`
+	Author      string              `json:"author,omitempty"`
+	Version     string              `json:"version,omitempty"`
+	MinOS       string              `json:"min_os,omitempty"`
+	Requires    []string            `json:"requires,omitempty"`
+	Missing     []string            `json:"missing,omitempty"` // Why requirements are unmet here
+	Runtime     string              `json:"runtime,omitempty"`
+	DocsURL     string              `json:"docs_url,omitempty"`
+	Params      []paramInfo         `json:"params,omitempty"`
+	Aliases     []string            `json:"aliases,omitempty"`
+	Module      string              `json:"module,omitempty"`
+	Privilege   string              `json:"privilege,omitempty"`
+	Compatible  bool                `json:"compatible"`
+	Reason      string              `json:"incompatible_reason,omitempty"`
+	Tags        map[string][]string `json:"tags,omitempty"`
+}
+
+// paramInfo is the JSON form of a declared script parameter.
+type paramInfo struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Pass        string   `json:"pass"`
+	Env         string   `json:"env,omitempty"`
+}
+
+// newScriptInfo collects the metadata of a script item.
+func newScriptInfo(s scripts.Item) scriptInfo {
+	info := scriptInfo{
+		Name:        s.Title(),
+		Path:        s.Description(),
+		Description: s.Summary(),
+		Aliases:     s.Aliases(),
+		Module:      s.Module(),
+		Privilege:   s.Privilege(),
+		Compatible:  s.IsCompatible(),
+		Reason:      s.Incompatibility(),
+	}
+	for _, u := range platform.CheckRequirements(s.Requires()) {
+		info.Missing = append(info.Missing, u.Reason)
+	}
+	if tags := s.GetTags(); tags != nil {
+		info.Author = tags.Author
+		info.Version = tags.Version
+		info.MinOS = tags.MinOS
+		info.Requires = tags.Requires
+		info.Runtime = tags.Runtime
+		info.DocsURL = tags.DocsURL
+		for _, p := range tags.Params {
+			param := paramInfo{
+				Name:        p.Name,
+				Type:        p.Type,
+				Options:     p.Options,
+				Required:    p.Required,
+				Default:     p.Default,
+				Description: p.Description,
+				Pass:        p.Pass,
+			}
+			if p.Pass == scripts.PassEnv {
+				param.Env = p.Env
+			}
+			info.Params = append(info.Params, param)
+		}
+		if len(tags.Tags) > 0 {
+			info.Tags = make(map[string][]string)
+			for _, tag := range tags.Tags {
+				info.Tags[tag.Category] = append(info.Tags[tag.Category], tag.Value)
+			}
+		}
+	}
+	return info
+}
+
+// runList implements the "list" command.
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print scripts and their metadata as JSON")
+	queryText := fs.String("q", "", "Only list scripts matching a search query, e.g. 'platform:linux -wsl'")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr list [-json] [-q query] [path]\n\n")
+		fmt.Fprintf(os.Stderr, "Lists the scripts of the synced repository, or of path if given.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if err := platform.RegisterInterpreterCommands(cfg.Interpreters); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid interpreters config: %v\n", err)
+		return 1
+	}
+
+	root := cfg.ScriptbinPath
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+	if _, err := os.Stat(root); err != nil {
+		fmt.Fprintf(os.Stderr, "No scripts at %s (run go-pwr once to sync the repository)\n", root)
+		return 1
+	}
+
+	// Tag rules come first, since they apply to the query too
+	scripts.LoadTagRules(root, scripts.TagRules{Synonyms: cfg.TagSynonyms, Parents: cfg.TagParents})
+	query, err := scripts.ParseQuery(*queryText)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid query: %v\n", err)
+		return 2
+	}
+
+	index := scripts.OpenIndex(scripts.IndexPath(cfg.CacheDir))
+	scripts.UseIndex(index)
+	defer index.Save()
+
+	items := scripts.FilterItemsByQuery(scripts.GetAllScriptsRecursively(root), query)
+	if *asJSON {
+		infos := make([]scriptInfo, 0, len(items))
+		for _, item := range items {
+			infos = append(infos, newScriptInfo(item.(scripts.Item)))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(infos); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing scripts: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, item := range items {
+		s := item.(scripts.Item)
+		if summary := s.Summary(); summary != "" {
+			fmt.Printf("%s - %s\n", s.Title(), summary)
+		} else {
+			fmt.Println(s.Title())
+		}
+	}
+	return 0
+}
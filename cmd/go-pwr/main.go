@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -25,23 +26,47 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "go-pwr v%s - Cross-platform script launcher\n\n", version)
 		fmt.Fprintf(os.Stderr, "USAGE:\n")
-		fmt.Fprintf(os.Stderr, "  go-pwr [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "  go-pwr [flags]\n")
+		fmt.Fprintf(os.Stderr, "  go-pwr <command> [args]\n\n")
+		fmt.Fprintf(os.Stderr, "COMMANDS:\n")
+		fmt.Fprintf(os.Stderr, "  cache dir           Show the cache directory\n")
+		fmt.Fprintf(os.Stderr, "  cache prune         Remove clones that are no longer used\n")
+		fmt.Fprintf(os.Stderr, "  lint [path]         Check a scriptbin checkout for tagging problems\n")
+		fmt.Fprintf(os.Stderr, "  list [-json] [-q]   List scripts with their descriptions, optionally filtered\n")
+		fmt.Fprintf(os.Stderr, "  run <script> [...]  Run a script in this terminal, passing parameters as flags\n")
+		fmt.Fprintf(os.Stderr, "  workflow list       List the workflows of the repository and config\n")
+		fmt.Fprintf(os.Stderr, "  workflow run <name> Run a workflow's scripts in order in this terminal\n")
+		fmt.Fprintf(os.Stderr, "  fav [add|rm ...]    List, pin or unpin favorite scripts\n")
+		fmt.Fprintf(os.Stderr, "  facts               Show the host facts scripts are matched against\n")
+		fmt.Fprintf(os.Stderr, "  doctor              Check tools, caches and the script index\n\n")
 		fmt.Fprintf(os.Stderr, "FLAGS:\n")
 		fmt.Fprintf(os.Stderr, "  -h, -help           Show this help message\n")
 		fmt.Fprintf(os.Stderr, "  -v, -version        Show version information\n")
 		fmt.Fprintf(os.Stderr, "  -show-repo          Show the current repository URL\n")
-		fmt.Fprintf(os.Stderr, "  -set-repo string    Set a custom repository URL\n")
+		fmt.Fprintf(os.Stderr, "  -set-repo string    Set a custom repository URL or .tar.gz/.zip archive\n")
+		fmt.Fprintf(os.Stderr, "  -sha256 string      Expected SHA-256 of the archive (with -set-repo)\n")
+		fmt.Fprintf(os.Stderr, "  -add-mirror string  Add a mirror to try when the repository is unreachable\n")
+		fmt.Fprintf(os.Stderr, "  -clear-mirrors      Remove all repository mirrors\n")
 		fmt.Fprintf(os.Stderr, "  -reset-repo         Reset to the default repository\n\n")
 		fmt.Fprintf(os.Stderr, "EXAMPLES:\n")
 		fmt.Fprintf(os.Stderr, "  go-pwr                                           Start the interactive TUI\n")
 		fmt.Fprintf(os.Stderr, "  go-pwr -show-repo                               Show current repository\n")
 		fmt.Fprintf(os.Stderr, "  go-pwr -set-repo https://github.com/user/repo.git  Set custom repository\n")
+		fmt.Fprintf(os.Stderr, "  go-pwr -set-repo ./scriptbin.tar.gz -sha256 <sum>  Use a release archive\n")
 		fmt.Fprintf(os.Stderr, "  go-pwr -reset-repo                              Reset to default repository\n\n")
 		fmt.Fprintf(os.Stderr, "For more information, visit: https://github.com/rocketpowerinc/go-pwr\n")
 	}
 
+	// Subcommands are dispatched before the global flags are parsed
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	// Parse command line flags
 	var setRepo = flag.String("set-repo", "", "Set a custom repository URL")
+	var repoChecksum = flag.String("sha256", "", "Expected SHA-256 of the repository archive")
+	var addMirror = flag.String("add-mirror", "", "Add a repository mirror URL")
+	var clearMirrors = flag.Bool("clear-mirrors", false, "Remove all repository mirrors")
 	var resetRepo = flag.Bool("reset-repo", false, "Reset to the default repository")
 	var showRepo = flag.Bool("show-repo", false, "Show the current repository URL")
 	var showVersion = flag.Bool("version", false, "Show version information")
@@ -75,7 +100,35 @@ func main() {
 			os.Exit(1)
 		}
 		fmt.Printf("Current repository: %s\n", cfg.RepoURL)
+		if cfg.RepoSHA256 != "" {
+			fmt.Printf("Archive checksum:   sha256:%s\n", cfg.RepoSHA256)
+		}
 		fmt.Printf("Default repository: %s\n", config.GetDefaultRepoURL())
+		for i, mirror := range cfg.RepoMirrors {
+			fmt.Printf("Mirror %d:           %s\n", i+1, mirror)
+		}
+		return
+	}
+
+	if *addMirror != "" {
+		if err := config.ValidateRepoURL(*addMirror); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid mirror URL: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.AddRepoMirror(*addMirror); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving mirror: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Mirror added: %s\n", *addMirror)
+		return
+	}
+
+	if *clearMirrors {
+		if err := config.ClearRepoMirrors(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing mirrors: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("All mirrors removed.")
 		return
 	}
 
@@ -89,15 +142,35 @@ func main() {
 	}
 
 	if *setRepo != "" {
-		if err := config.ValidateRepoURL(*setRepo); err != nil {
+		repoURL := *setRepo
+		if err := config.ValidateRepoURL(repoURL); err != nil {
 			fmt.Fprintf(os.Stderr, "Invalid repository URL: %v\n", err)
 			os.Exit(1)
 		}
-		if err := config.SaveRepoURL(*setRepo); err != nil {
+		// Store local archives and directories by absolute path so they
+		// resolve from any directory
+		if (config.IsArchiveSource(repoURL) || config.IsLocalSource(repoURL)) && !strings.Contains(repoURL, "://") {
+			if absPath, err := filepath.Abs(repoURL); err == nil {
+				repoURL = absPath
+			}
+		}
+		if *repoChecksum != "" {
+			if err := config.ValidateChecksum(*repoChecksum); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid checksum: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := config.SaveRepoURL(repoURL); err != nil {
 			fmt.Fprintf(os.Stderr, "Error saving repository URL: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Repository set to: %s\n", *setRepo)
+		if *repoChecksum != "" {
+			if err := config.SaveRepoChecksum(*repoChecksum); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving checksum: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("Repository set to: %s\n", repoURL)
 		return
 	}
 
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rocketpowerinc/go-pwr/internal/config"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// runRun implements the "run" command, which runs a script in the
+// foreground without the TUI.
+func runRun(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr run <script> [-yes] [-force] [-param value ...] [-- script args]\n\n")
+		fmt.Fprintf(os.Stderr, "<script> is a name relative to the repository, a file name, an alias, or a path starting with / or ./.\n")
+		fmt.Fprintf(os.Stderr, "-yes runs scripts that need administrator rights without asking.\n")
+		fmt.Fprintf(os.Stderr, "-force runs scripts even if their requirements are missing.\n")
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if err := platform.RegisterInterpreterCommands(cfg.Interpreters); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid interpreters config: %v\n", err)
+		return 1
+	}
+
+	item, err := scripts.FindScript(cfg.ScriptbinPath, args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if item.Privilege() == scripts.PrivilegeUser && platform.IsElevated() {
+		fmt.Fprintf(os.Stderr, "Error: %s must not run as root\n", item.Title())
+		return 1
+	}
+
+	// Every declared parameter becomes a flag
+	fs := flag.NewFlagSet("run "+args[0], flag.ContinueOnError)
+	yes, force := new(bool), new(bool)
+	values := make(map[string]*string)
+	bools := make(map[string]*bool)
+	for _, p := range item.Params() {
+		usage := p.Description
+		if p.Type == scripts.ParamEnum {
+			usage = strings.TrimSpace(usage + " (" + strings.Join(p.Options, ", ") + ")")
+		}
+		if p.Type == scripts.ParamBool {
+			bools[p.Name] = fs.Bool(p.Name, p.Default == "true", usage)
+		} else {
+			values[p.Name] = fs.String(p.Name, p.Default, usage)
+		}
+	}
+	if _, taken := bools["yes"]; !taken && values["yes"] == nil {
+		fs.BoolVar(yes, "yes", false, "Run without asking for confirmation, even if the script needs administrator rights")
+	}
+	if _, taken := bools["force"]; !taken && values["force"] == nil {
+		fs.BoolVar(force, "force", false, "Run even if the script's requirements are missing")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr run %s [-param value ...] [-- script args]\n", args[0])
+		if summary := item.Summary(); summary != "" {
+			fmt.Fprintf(os.Stderr, "\n%s\n", summary)
+		}
+		if len(item.Params()) > 0 {
+			fmt.Fprintf(os.Stderr, "\nParameters:\n")
+			fs.PrintDefaults()
+		}
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	entered := make(map[string]string)
+	for name, value := range values {
+		entered[name] = *value
+	}
+	for name, value := range bools {
+		entered[name] = fmt.Sprint(*value)
+	}
+
+	paramArgs, env, err := scripts.ParamValues(item.Params(), entered)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if unmet := platform.CheckRequirements(item.Requires()); len(unmet) > 0 && !*force {
+		fmt.Fprintf(os.Stderr, "Error: %s is missing requirements:\n", item.Title())
+		for _, u := range unmet {
+			fmt.Fprintf(os.Stderr, "  - %s\n", u.Reason)
+			for _, hint := range u.InstallHints() {
+				fmt.Fprintf(os.Stderr, "      %s\n", hint)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Install them, or pass -force to run anyway.\n")
+		return 1
+	}
+
+	opts := platform.RunOptions{
+		Args: append(append(append([]string{}, item.Args()...), paramArgs...), fs.Args()...),
+		Env:  env,
+	}
+	if item.NeedsAdmin() && !platform.IsElevated() {
+		elevate, err := platform.ElevationCommand(cfg.ElevateCommand)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s needs administrator rights, but %v\n", item.Title(), err)
+			return 1
+		}
+		if !*yes && !confirm(fmt.Sprintf("%s requires administrator rights. Run it via %s?", item.Title(), elevate)) {
+			fmt.Fprintln(os.Stderr, "Cancelled")
+			return 1
+		}
+		opts.Elevate = elevate
+	}
+	if err := platform.RunScript(item.Description(), opts); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
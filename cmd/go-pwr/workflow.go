@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rocketpowerinc/go-pwr/internal/config"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// runWorkflow implements the "workflow" command.
+func runWorkflow(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr workflow <list|run> [flags]\n")
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if err := platform.RegisterInterpreterCommands(cfg.Interpreters); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid interpreters config: %v\n", err)
+		return 1
+	}
+	workflows, err := scripts.LoadWorkflows(cfg.ScriptbinPath, cfg.Workflows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading workflows: %v\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "list":
+		if len(workflows) == 0 {
+			fmt.Println("No workflows defined.")
+			return 0
+		}
+		for _, workflow := range workflows {
+			fmt.Printf("%-24s %d steps  %s\n", workflow.Name, len(workflow.Steps), workflow.Description)
+		}
+		return 0
+	case "run":
+		return runWorkflowSteps(cfg, workflows, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown workflow command: %s\n", args[0])
+		return 2
+	}
+}
+
+// runWorkflowSteps implements "workflow run", which runs the steps of a
+// workflow one after another in this terminal.
+func runWorkflowSteps(cfg *config.Config, workflows []scripts.Workflow, args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintf(os.Stderr, "Usage: go-pwr workflow run <name> [-yes] [-force] [-on-error stop|continue] [-set name=value ...]\n")
+		return 2
+	}
+	workflow, ok := scripts.FindWorkflow(workflows, args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: no workflow named %q\n", args[0])
+		return 1
+	}
+
+	fs := flag.NewFlagSet("workflow run "+args[0], flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "Run steps that need administrator rights without asking")
+	force := fs.Bool("force", false, "Run even if steps' requirements are missing")
+	onError := fs.String("on-error", workflow.OnError, "What to do when a step fails: stop or continue")
+	set := paramFlags{}
+	fs.Var(set, "set", "Set a parameter for every step, as name=value (repeatable)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	workflow.OnError = *onError
+	if len(set) > 0 {
+		params := make(map[string]string, len(workflow.Params)+len(set))
+		for name, value := range workflow.Params {
+			params[name] = value
+		}
+		for name, value := range set {
+			params[name] = value
+		}
+		workflow.Params = params
+	}
+
+	steps, err := workflow.Plan(cfg.ScriptbinPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: workflow %s cannot run:\n%v\n", workflow.Name, err)
+		return 1
+	}
+
+	// Check every step before running any, so a workflow does not stop
+	// half-way for something that could have been known up front
+	var problems, admin []string
+	for i, step := range steps {
+		if step.Item.Privilege() == scripts.PrivilegeUser && platform.IsElevated() {
+			problems = append(problems, fmt.Sprintf("step %d: %s must not run as root", i+1, step.Item.Title()))
+		}
+		if !*force {
+			for _, u := range platform.CheckRequirements(step.Item.Requires()) {
+				problem := fmt.Sprintf("step %d: %s", i+1, u.Reason)
+				if hints := u.InstallHints(); len(hints) > 0 {
+					problem += " (" + strings.Join(hints, " or ") + ")"
+				}
+				problems = append(problems, problem)
+			}
+		}
+		if step.Item.NeedsAdmin() && !platform.IsElevated() {
+			admin = append(admin, step.Item.Title())
+		}
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: workflow %s cannot run:\n", workflow.Name)
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		if !*force {
+			fmt.Fprintf(os.Stderr, "Install what is missing, or pass -force to run anyway.\n")
+		}
+		return 1
+	}
+
+	var elevate string
+	if len(admin) > 0 {
+		elevate, err = platform.ElevationCommand(cfg.ElevateCommand)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s need administrator rights, but %v\n", strings.Join(admin, ", "), err)
+			return 1
+		}
+		if !*yes && !confirm(fmt.Sprintf("%s require administrator rights. Run them via %s?", strings.Join(admin, ", "), elevate)) {
+			fmt.Fprintln(os.Stderr, "Cancelled")
+			return 1
+		}
+	}
+
+	run := scripts.NewWorkflowRun(workflow, steps)
+	for i := 0; i >= 0; {
+		step := steps[i]
+		opts := step.Options
+		if step.Item.NeedsAdmin() && !platform.IsElevated() {
+			opts.Elevate = elevate
+		}
+		fmt.Printf("▶ [%d/%d] %s\n", i+1, len(steps), step.Item.Title())
+		run.Start(i)
+		i = run.Finish(i, platform.RunScript(step.Item.Description(), opts))
+	}
+
+	fmt.Printf("\nWorkflow %s:\n", workflow.Name)
+	for i, step := range steps {
+		line := fmt.Sprintf("  %s %s", run.Status[i].Icon(), step.Item.Title())
+		if run.Errors[i] != nil {
+			line += ": " + run.Errors[i].Error()
+		}
+		fmt.Println(line)
+	}
+	if failed := run.Failed(); failed > 0 {
+		fmt.Printf("%d of %d steps failed\n", failed, len(steps))
+		return 1
+	}
+	fmt.Printf("All %d steps succeeded\n", len(steps))
+	return 0
+}
+
+// paramFlags collects repeated -set name=value flags.
+type paramFlags map[string]string
+
+func (p paramFlags) String() string {
+	var pairs []string
+	for name, value := range p {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p paramFlags) Set(value string) error {
+	name, value, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value")
+	}
+	p[name] = value
+	return nil
+}
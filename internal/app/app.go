@@ -2,9 +2,17 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
 	"github.com/rocketpowerinc/go-pwr/internal/config"
 	"github.com/rocketpowerinc/go-pwr/internal/git"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
 	"github.com/rocketpowerinc/go-pwr/internal/ui"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
 )
 
 // Run starts the go-pwr application.
@@ -15,11 +23,37 @@ func Run() error {
 		return err
 	}
 
-	// Ensure repository is cloned/updated
-	if err := git.EnsureRepository(cfg); err != nil {
+	// Let the user add or override script interpreters
+	if err := platform.RegisterInterpreterCommands(cfg.Interpreters); err != nil {
+		return fmt.Errorf("invalid interpreters config: %v", err)
+	}
+
+	// Ensure repository is cloned/updated, letting Ctrl+C abort a slow sync
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	fmt.Println("Syncing scripts... (Ctrl+C to cancel)")
+	err = git.EnsureRepository(ctx, cfg)
+	stop()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("repository sync cancelled")
+		}
 		return err
 	}
 
+	// Normalize tags with the repository's and the user's rules
+	scripts.LoadTagRules(cfg.ScriptbinPath, scripts.TagRules{Synonyms: cfg.TagSynonyms, Parents: cfg.TagParents})
+
+	// Index script headers so listings don't re-parse unchanged scripts
+	index := scripts.OpenIndex(scripts.IndexPath(cfg.CacheDir))
+	if err := index.Refresh(cfg.ScriptbinPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to index scripts: %v\n", err)
+	}
+	scripts.UseIndex(index)
+
 	// Start the UI
-	return ui.Start(cfg)
+	err = ui.Start(cfg)
+	if saveErr := index.Save(); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save the script index: %v\n", saveErr)
+	}
+	return err
 }
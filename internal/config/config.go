@@ -2,30 +2,81 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
 )
 
 // Config holds the application configuration.
 type Config struct {
-	ScriptbinPath string `json:"scriptbin_path"`
-	RepoURL       string `json:"repo_url"`
-	Theme         string `json:"theme"` // Store the theme name
+	ScriptbinPath string        `json:"scriptbin_path"`
+	CacheDir      string        `json:"cache_dir"` // Base directory for clones and cached data
+	RepoURL       string        `json:"repo_url"`
+	Theme         string        `json:"theme"` // Store the theme name
+	GitClient     string        `json:"git_client"`
+	RepoSHA256    string        `json:"repo_sha256"`  // Expected checksum for archive sources
+	RepoMirrors   []string      `json:"repo_mirrors"` // Tried in order when RepoURL fails
+	ActiveRepoURL string        `json:"-"`            // URL the scripts were last synced from
+	GitTimeout    time.Duration `json:"-"`            // Limit for each clone, fetch or download
+	Submodules    string        `json:"submodules"`   // recursive, top or off
+
+	Interpreters   map[string]string `json:"interpreters"`    // Extra interpreters by file extension
+	CompatibleOnly bool              `json:"compatible_only"` // Hide scripts whose tags exclude this host
+	ElevateCommand string            `json:"elevate_command"` // sudo, doas or auto
+
+	TagSynonyms map[string]string   `json:"tag_synonyms"` // Tag value to canonical value
+	TagParents  map[string][]string `json:"tag_parents"`  // Tag value to broader values it implies
+
+	Workflows map[string]scripts.Workflow `json:"workflows"` // The user's own workflows, by name
+	Favorites []string                    `json:"favorites"` // Pinned scripts of RepoURL, relative to it
 }
 
 // UserConfig represents the persistent user configuration
 type UserConfig struct {
-	Theme   string `json:"theme"`
-	RepoURL string `json:"repo_url,omitempty"` // Custom repository URL
+	Theme      string `json:"theme"`
+	RepoURL    string `json:"repo_url,omitempty"`    // Custom repository URL
+	GitClient  string `json:"git_client,omitempty"`  // auto, system or builtin
+	RepoSHA256 string `json:"repo_sha256,omitempty"` // Checksum for archive sources
+	CacheDir   string `json:"cache_dir,omitempty"`   // Overrides the default cache directory
+
+	RepoMirrors []string `json:"repo_mirrors,omitempty"` // Mirrors of RepoURL, in order
+
+	GitTimeoutSeconds int    `json:"git_timeout_seconds,omitempty"` // 0 keeps the default
+	Submodules        string `json:"submodules,omitempty"`          // recursive, top or off
+
+	// Interpreters maps file extensions to command lines, e.g. {".py": "python3 -u"}
+	Interpreters map[string]string `json:"interpreters,omitempty"`
+
+	CompatibleOnly bool   `json:"compatible_only,omitempty"` // Hide scripts whose tags exclude this host
+	ElevateCommand string `json:"elevate_command,omitempty"` // sudo, doas or auto
+
+	// TagSynonyms and TagParents add to the repository's tag rules, e.g.
+	// {"mac": "macos"} and {"ubuntu": ["debian-family"]}
+	TagSynonyms map[string]string   `json:"tag_synonyms,omitempty"`
+	TagParents  map[string][]string `json:"tag_parents,omitempty"`
+
+	// Workflows add to or replace those of the repository's root manifest
+	Workflows map[string]scripts.Workflow `json:"workflows,omitempty"`
+
+	// Favorites are the pinned scripts of each repository URL by path in
+	// that repository, e.g. "linux/update.sh", so they outlive re-clones
+	Favorites map[string][]string `json:"favorites,omitempty"`
 }
 
+// DefaultGitTimeout bounds each clone, fetch or archive download.
+const DefaultGitTimeout = 5 * time.Minute
+
 // Load loads the application configuration.
 func Load() (*Config, error) {
-	scriptbinPath, err := getScriptbinPath()
+	cacheDir, err := getCacheDir()
 	if err != nil {
 		return nil, err
 	}
@@ -34,9 +85,15 @@ func Load() (*Config, error) {
 	defaultRepoURL := "https://github.com/rocketpowerinc/scriptbin.git"
 
 	config := &Config{
-		ScriptbinPath: scriptbinPath,
-		RepoURL:       defaultRepoURL,
-		Theme:         "Ocean Breeze", // Default theme
+		CacheDir:  cacheDir,
+		RepoURL:   defaultRepoURL,
+		Theme:     "Ocean Breeze", // Default theme
+		GitClient: "auto",         // Use git when installed, built-in otherwise
+
+		GitTimeout: DefaultGitTimeout,
+		Submodules: "recursive",
+
+		ElevateCommand: "auto", // sudo, falling back to doas
 	}
 
 	// Load user preferences
@@ -47,9 +104,34 @@ func Load() (*Config, error) {
 		// Use custom repo URL if set, otherwise keep default
 		if userConfig.RepoURL != "" {
 			config.RepoURL = userConfig.RepoURL
+			config.RepoSHA256 = userConfig.RepoSHA256
+		}
+		config.RepoMirrors = userConfig.RepoMirrors
+		config.Interpreters = userConfig.Interpreters
+		config.CompatibleOnly = userConfig.CompatibleOnly
+		config.TagSynonyms = userConfig.TagSynonyms
+		config.TagParents = userConfig.TagParents
+		config.Workflows = userConfig.Workflows
+		config.Favorites = userConfig.Favorites[config.RepoURL]
+		if userConfig.ElevateCommand != "" {
+			config.ElevateCommand = userConfig.ElevateCommand
+		}
+		if userConfig.Submodules != "" {
+			config.Submodules = userConfig.Submodules
+		}
+		if userConfig.GitTimeoutSeconds > 0 {
+			config.GitTimeout = time.Duration(userConfig.GitTimeoutSeconds) * time.Second
+		}
+		if userConfig.GitClient != "" {
+			config.GitClient = userConfig.GitClient
+		}
+		if userConfig.CacheDir != "" {
+			config.CacheDir = userConfig.CacheDir
 		}
 	}
 
+	config.ScriptbinPath = RepositoryPath(config)
+
 	return config, nil
 }
 
@@ -64,6 +146,44 @@ func SaveTheme(themeName string) error {
 	return saveUserConfig(userConfig)
 }
 
+// SaveCompatibleOnly saves whether incompatible scripts are hidden
+func SaveCompatibleOnly(enabled bool) error {
+	userConfig, _ := loadUserConfig() // Load existing config or create new
+	if userConfig == nil {
+		userConfig = &UserConfig{}
+	}
+
+	userConfig.CompatibleOnly = enabled
+	return saveUserConfig(userConfig)
+}
+
+// SaveFavorites saves the pinned scripts of a repository
+func SaveFavorites(repoURL string, favorites []string) error {
+	userConfig, _ := loadUserConfig() // Load existing config or create new
+	if userConfig == nil {
+		userConfig = &UserConfig{}
+	}
+
+	if len(favorites) == 0 {
+		delete(userConfig.Favorites, repoURL)
+	} else {
+		if userConfig.Favorites == nil {
+			userConfig.Favorites = make(map[string][]string)
+		}
+		userConfig.Favorites[repoURL] = favorites
+	}
+	return saveUserConfig(userConfig)
+}
+
+// LoadFavorites returns the pinned scripts of a repository
+func LoadFavorites(repoURL string) []string {
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return nil
+	}
+	return userConfig.Favorites[repoURL]
+}
+
 // SaveRepoURL saves the user's custom repository URL
 func SaveRepoURL(repoURL string) error {
 	userConfig, _ := loadUserConfig() // Load existing config or create new
@@ -72,6 +192,46 @@ func SaveRepoURL(repoURL string) error {
 	}
 	
 	userConfig.RepoURL = repoURL
+	userConfig.RepoSHA256 = "" // A checksum only applies to the source it was set for
+	userConfig.RepoMirrors = nil
+	return saveUserConfig(userConfig)
+}
+
+// AddRepoMirror appends a mirror URL for the current repository
+func AddRepoMirror(mirrorURL string) error {
+	userConfig, _ := loadUserConfig() // Load existing config or create new
+	if userConfig == nil {
+		userConfig = &UserConfig{}
+	}
+
+	for _, existing := range userConfig.RepoMirrors {
+		if existing == mirrorURL {
+			return nil
+		}
+	}
+	userConfig.RepoMirrors = append(userConfig.RepoMirrors, mirrorURL)
+	return saveUserConfig(userConfig)
+}
+
+// ClearRepoMirrors removes all mirror URLs
+func ClearRepoMirrors() error {
+	userConfig, _ := loadUserConfig() // Load existing config or create new
+	if userConfig == nil {
+		userConfig = &UserConfig{}
+	}
+
+	userConfig.RepoMirrors = nil
+	return saveUserConfig(userConfig)
+}
+
+// SaveRepoChecksum saves the expected SHA-256 of the repository archive
+func SaveRepoChecksum(sha256sum string) error {
+	userConfig, _ := loadUserConfig() // Load existing config or create new
+	if userConfig == nil {
+		userConfig = &UserConfig{}
+	}
+
+	userConfig.RepoSHA256 = strings.ToLower(strings.TrimPrefix(sha256sum, "sha256:"))
 	return saveUserConfig(userConfig)
 }
 
@@ -83,6 +243,8 @@ func ResetToDefaultRepo() error {
 	}
 	
 	userConfig.RepoURL = "" // Empty string means use default
+	userConfig.RepoSHA256 = ""
+	userConfig.RepoMirrors = nil
 	return saveUserConfig(userConfig)
 }
 
@@ -159,6 +321,16 @@ func ValidateRepoURL(repoURL string) error {
 		return fmt.Errorf("repository URL cannot be empty")
 	}
 
+	// Local directories are used in place, without cloning
+	if IsLocalSource(repoURL) {
+		return nil
+	}
+
+	// Release archives are fetched over HTTP(S) or read from a local file
+	if IsArchiveSource(repoURL) {
+		return validateArchiveSource(repoURL)
+	}
+
 	// Parse the URL
 	u, err := url.Parse(repoURL)
 	if err != nil {
@@ -186,22 +358,88 @@ func ValidateRepoURL(repoURL string) error {
 	return nil
 }
 
-// getScriptbinPath returns the path where scriptbin should be stored
-// It now uses a fixed location in $HOME/Downloads/Temp/scriptbin
-func getScriptbinPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+// IsArchiveSource reports whether the source is a .tar.gz or .zip archive
+// rather than a git repository.
+func IsArchiveSource(source string) bool {
+	lower := strings.ToLower(source)
+	if u, err := url.Parse(lower); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		lower = u.Path
+	}
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".zip")
+}
+
+// IsLocalSource reports whether the source is an existing local directory,
+// such as a checkout someone is editing, rather than something to download.
+func IsLocalSource(source string) bool {
+	if strings.Contains(source, "://") || IsArchiveSource(source) {
+		return false
+	}
+	info, err := os.Stat(source)
+	return err == nil && info.IsDir()
+}
+
+// ValidateChecksum validates a hex-encoded SHA-256 checksum
+func ValidateChecksum(sha256sum string) error {
+	sum := strings.TrimPrefix(strings.ToLower(sha256sum), "sha256:")
+	if len(sum) != 64 || strings.Trim(sum, "0123456789abcdef") != "" {
+		return fmt.Errorf("checksum must be 64 hexadecimal characters")
+	}
+	return nil
+}
+
+// validateArchiveSource validates an archive URL or local archive path
+func validateArchiveSource(source string) error {
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		if u.Host == "" {
+			return fmt.Errorf("archive URL is missing a host")
+		}
+		return nil
+	}
+
+	info, err := os.Stat(source)
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %v", err)
+		return fmt.Errorf("archive not found: %v", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("archive path is a directory: %s", source)
 	}
+	return nil
+}
 
-	// Use fixed location in Downloads/Temp/scriptbin
-	scriptbinPath := filepath.Join(homeDir, "Downloads", "Temp", "scriptbin")
-	
-	// Ensure the parent directories exist
-	parentDir := filepath.Dir(scriptbinPath)
-	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create parent directories: %v", err)
+// RepositoryPath returns where the configured source is stored locally.
+// Each source gets its own directory, named after a hash of its URL, so
+// repositories with the same name from different owners never collide. A
+// local directory source is used where it is.
+func RepositoryPath(cfg *Config) string {
+	if IsLocalSource(cfg.RepoURL) {
+		if path, err := filepath.Abs(cfg.RepoURL); err == nil {
+			return path
+		}
 	}
+	return filepath.Join(RepositoriesDir(cfg), RepoHash(cfg.RepoURL))
+}
+
+// RepositoriesDir returns the directory that holds all local clones.
+func RepositoriesDir(cfg *Config) string {
+	return filepath.Join(cfg.CacheDir, "repos")
+}
+
+// RepoHash returns the directory name used for a source URL.
+func RepoHash(repoURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(repoURL)))
+	return hex.EncodeToString(sum[:])[:16]
+}
 
-	return scriptbinPath, nil
+// getCacheDir returns the default base directory for go-pwr's cached data,
+// $XDG_CACHE_HOME/go-pwr or the platform equivalent.
+func getCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			return "", fmt.Errorf("failed to get user cache directory: %v", err)
+		}
+		cacheDir = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(cacheDir, "go-pwr"), nil
 }
@@ -0,0 +1,319 @@
+package git
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveChecksumFile records, inside an unpacked archive, the pinned
+// checksum it was verified against. Hidden files are never listed.
+const archiveChecksumFile = ".go-pwr-sha256"
+
+// ensureArchive replaces the contents of dir with the unpacked archive. The
+// previous contents are kept if the archive cannot be fetched or verified.
+// With a pinned checksum the archive cannot change, so it is only fetched
+// when dir holds a different one.
+func ensureArchive(ctx context.Context, source, sha256sum, dir string) error {
+	if sha256sum != "" && archiveChecksum(dir) == normalizeChecksum(sha256sum) {
+		return nil
+	}
+
+	partial := dir + ".partial"
+	if err := os.RemoveAll(partial); err != nil {
+		return fmt.Errorf("failed to remove partial download: %v", err)
+	}
+	if err := fetchArchive(ctx, source, sha256sum, partial); err != nil {
+		os.RemoveAll(partial)
+		return err
+	}
+	if sha256sum != "" {
+		record := filepath.Join(partial, archiveChecksumFile)
+		if err := os.WriteFile(record, []byte(normalizeChecksum(sha256sum)+"\n"), 0644); err != nil {
+			os.RemoveAll(partial)
+			return fmt.Errorf("failed to record archive checksum: %v", err)
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove old repository: %v", err)
+	}
+	if err := os.Rename(partial, dir); err != nil {
+		return fmt.Errorf("failed to move archive into place: %v", err)
+	}
+	return nil
+}
+
+// archiveChecksum returns the checksum recorded in an unpacked archive, or
+// an empty string.
+func archiveChecksum(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, archiveChecksumFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// normalizeChecksum lower-cases a checksum and strips its "sha256:" prefix.
+func normalizeChecksum(sha256sum string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(sha256sum)), "sha256:")
+}
+
+// fetchArchive downloads or opens the archive at source, verifies it against
+// sha256sum when one is given and unpacks it into dir.
+func fetchArchive(ctx context.Context, source, sha256sum, dir string) error {
+	tmp, err := os.CreateTemp("", "go-pwr-archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	// Copy the archive while hashing it, so it is only read once
+	hasher := sha256.New()
+	if err := copyArchive(ctx, source, io.MultiWriter(tmp, hasher)); err != nil {
+		return err
+	}
+
+	if sha256sum != "" {
+		expected := normalizeChecksum(sha256sum)
+		actual := hex.EncodeToString(hasher.Sum(nil))
+		if actual != expected {
+			return fmt.Errorf("archive checksum mismatch: expected %s, got %s", expected, actual)
+		}
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	lower := strings.ToLower(archivePath(source))
+	if strings.HasSuffix(lower, ".zip") {
+		info, err := tmp.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to stat archive: %v", err)
+		}
+		return extractZip(tmp, info.Size(), dir)
+	}
+	return extractTarGz(tmp, dir)
+}
+
+// copyArchive writes the contents of a remote or local archive to w.
+func copyArchive(ctx context.Context, source string, w io.Writer) error {
+	var r io.ReadCloser
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return fmt.Errorf("invalid archive URL: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctxErr := contextError(ctx, "download"); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("failed to download archive: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("failed to download archive: %s", resp.Status)
+		}
+		r = resp.Body
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %v", err)
+		}
+		r = file
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		if ctxErr := contextError(ctx, "download"); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("failed to read archive: %v", err)
+	}
+	return nil
+}
+
+// archivePath returns the path component of an archive URL or file path.
+func archivePath(source string) string {
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return u.Path
+	}
+	return source
+}
+
+// extractTarGz unpacks a gzip-compressed tarball into dir.
+func extractTarGz(file *os.File, dir string) error {
+	// Read the entry names first so a shared top-level directory can be
+	// stripped. Only files and directories count: GitHub tarballs start with
+	// a pax_global_header entry, and links are not extracted.
+	var names []string
+	err := walkTarGz(file, func(header *tar.Header, _ io.Reader) error {
+		if header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeDir {
+			names = append(names, header.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive: %v", err)
+	}
+
+	prefix := commonRoot(names)
+	return walkTarGz(file, func(header *tar.Header, r io.Reader) error {
+		target, ok := archiveTarget(dir, header.Name, prefix)
+		if !ok {
+			return nil
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %v", target, err)
+			}
+		case tar.TypeReg:
+			return writeArchiveFile(target, header.FileInfo().Mode(), r)
+		}
+		return nil
+	})
+}
+
+// walkTarGz calls fn for every entry of a gzip-compressed tarball.
+func walkTarGz(r io.Reader, fn func(*tar.Header, io.Reader) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip archive: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %v", err)
+		}
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// extractZip unpacks a zip archive into dir.
+func extractZip(r io.ReaderAt, size int64, dir string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %v", err)
+	}
+
+	// As with tarballs, only files and directories are extracted; symbolic
+	// links could point outside dir
+	var files []*zip.File
+	var names []string
+	for _, f := range zr.File {
+		if mode := f.Mode(); mode.IsDir() || mode.IsRegular() {
+			files = append(files, f)
+			names = append(names, f.Name)
+		}
+	}
+
+	prefix := commonRoot(names)
+	for _, f := range files {
+		target, ok := archiveTarget(dir, f.Name, prefix)
+		if !ok {
+			continue
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %v", target, err)
+			}
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %v", f.Name, err)
+		}
+		err = writeArchiveFile(target, f.Mode(), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArchiveFile writes an extracted file, keeping its executable bits.
+func writeArchiveFile(target string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(target), err)
+	}
+	perm := mode.Perm() | 0600
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", target, err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, r); err != nil {
+		return fmt.Errorf("failed to write %s: %v", target, err)
+	}
+	return nil
+}
+
+// commonRoot returns the single top-level directory shared by every entry
+// (as release archives usually have), or an empty string.
+func commonRoot(names []string) string {
+	root := ""
+	for _, name := range names {
+		name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+		if name == "" {
+			continue
+		}
+		first, _, found := strings.Cut(name, "/")
+		if !found {
+			return "" // A file at the top level
+		}
+		if root == "" {
+			root = first
+		} else if root != first {
+			return ""
+		}
+	}
+	if root == "" {
+		return ""
+	}
+	return root + "/"
+}
+
+// archiveTarget maps an archive entry to a path inside dir, rejecting
+// entries that would escape it.
+func archiveTarget(dir, name, prefix string) (string, bool) {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+	name = strings.TrimPrefix(name, prefix)
+	if name == "" {
+		return "", false
+	}
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if target != dir && !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return "", false
+	}
+	return target, true
+}
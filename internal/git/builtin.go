@@ -0,0 +1,111 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// remoteHeadRef is where the built-in client stores the fetched remote HEAD.
+const remoteHeadRef = "refs/remotes/origin/HEAD"
+
+// builtinClient implements Client in-process, so go-pwr works on machines
+// that do not have git installed yet.
+type builtinClient struct{}
+
+func (builtinClient) Name() string { return ClientBuiltin }
+
+func (builtinClient) Clone(ctx context.Context, url, dir string) error {
+	_, err := gogit.PlainCloneContext(ctx, dir, false, &gogit.CloneOptions{URL: url})
+	if err != nil {
+		if ctxErr := contextError(ctx, "clone"); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("git clone error: %v", err)
+	}
+	return nil
+}
+
+func (builtinClient) Fetch(ctx context.Context, dir string) (string, error) {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %v", err)
+	}
+
+	err = repo.FetchContext(ctx, &gogit.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{"+HEAD:" + remoteHeadRef},
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		if ctxErr := contextError(ctx, "fetch"); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("git fetch error: %v", err)
+	}
+
+	ref, err := repo.Reference(plumbing.ReferenceName(remoteHeadRef), true)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve remote HEAD: %v", err)
+	}
+	return ref.Hash().String(), nil
+}
+
+func (builtinClient) Checkout(ctx context.Context, dir, rev string) error {
+	if err := contextError(ctx, "checkout"); err != nil {
+		return err
+	}
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %v", err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %v", rev, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to open worktree: %v", err)
+	}
+
+	if err := worktree.Checkout(&gogit.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return fmt.Errorf("git checkout error: %v", err)
+	}
+	return nil
+}
+
+func (builtinClient) UpdateSubmodules(ctx context.Context, dir string, depth int) error {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %v", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to open worktree: %v", err)
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return fmt.Errorf("failed to read submodules: %v", err)
+	}
+
+	err = submodules.UpdateContext(ctx, &gogit.SubmoduleUpdateOptions{
+		Init:              true,
+		RecurseSubmodules: gogit.SubmoduleRescursivity(depth - 1),
+	})
+	if err != nil {
+		if ctxErr := contextError(ctx, "submodule update"); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("git submodule update error: %v", err)
+	}
+	return nil
+}
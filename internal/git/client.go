@@ -0,0 +1,166 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Client names accepted by the git_client configuration setting.
+const (
+	ClientAuto    = "auto"
+	ClientSystem  = "system"
+	ClientBuiltin = "builtin"
+)
+
+// Client performs the git operations needed to maintain a script checkout.
+type Client interface {
+	// Name returns a short identifier for the client.
+	Name() string
+	// Clone clones url into dir.
+	Clone(ctx context.Context, url, dir string) error
+	// Fetch updates the remote-tracking refs of the checkout in dir and
+	// returns the commit the remote HEAD points to.
+	Fetch(ctx context.Context, dir string) (string, error)
+	// Checkout forcibly checks out rev in dir, discarding local changes.
+	Checkout(ctx context.Context, dir, rev string) error
+	// UpdateSubmodules initializes and updates the submodules of the checkout
+	// in dir, descending at most depth levels.
+	UpdateSubmodules(ctx context.Context, dir string, depth int) error
+}
+
+// Submodule modes accepted by the submodules configuration setting.
+const (
+	SubmodulesRecursive = "recursive"
+	SubmodulesTop       = "top"
+	SubmodulesOff       = "off"
+)
+
+// maxSubmoduleDepth bounds recursive submodule updates.
+const maxSubmoduleDepth = 10
+
+// submoduleDepth converts a submodules setting into a recursion depth.
+func submoduleDepth(mode string) (int, error) {
+	switch strings.ToLower(mode) {
+	case "", SubmodulesRecursive:
+		return maxSubmoduleDepth, nil
+	case SubmodulesTop:
+		return 1, nil
+	case SubmodulesOff:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unknown submodules mode %q (supported: recursive, top, off)", mode)
+	}
+}
+
+// NewClient returns the git client selected by name. An empty name or
+// "auto" uses the git binary when it is installed and the built-in
+// implementation otherwise.
+func NewClient(name string) (Client, error) {
+	switch strings.ToLower(name) {
+	case "", ClientAuto:
+		if _, err := exec.LookPath("git"); err == nil {
+			return systemClient{}, nil
+		}
+		return builtinClient{}, nil
+	case ClientSystem:
+		if _, err := exec.LookPath("git"); err != nil {
+			return nil, fmt.Errorf("git client %q selected but git is not installed", ClientSystem)
+		}
+		return systemClient{}, nil
+	case ClientBuiltin:
+		return builtinClient{}, nil
+	default:
+		return nil, fmt.Errorf("unknown git client %q (supported: auto, system, builtin)", name)
+	}
+}
+
+// systemClient shells out to the git binary.
+type systemClient struct{}
+
+func (systemClient) Name() string { return ClientSystem }
+
+func (systemClient) Clone(ctx context.Context, url, dir string) error {
+	_, err := runGit(ctx, "", "clone", url, dir)
+	return err
+}
+
+func (systemClient) Fetch(ctx context.Context, dir string) (string, error) {
+	if _, err := runGit(ctx, dir, "fetch", "origin", "HEAD"); err != nil {
+		return "", err
+	}
+	out, err := runGit(ctx, dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (systemClient) Checkout(ctx context.Context, dir, rev string) error {
+	_, err := runGit(ctx, dir, "checkout", "--force", "--detach", rev)
+	return err
+}
+
+func (systemClient) UpdateSubmodules(ctx context.Context, dir string, depth int) error {
+	args := []string{"submodule", "update", "--init", "--force"}
+	if depth > 1 {
+		args = append(args, "--recursive")
+	}
+	// Pick up submodule URL changes before updating
+	if _, err := runGit(ctx, dir, "submodule", "sync", "--recursive"); err != nil {
+		return err
+	}
+	_, err := runGit(ctx, dir, args...)
+	return err
+}
+
+// runGit runs a git subcommand, optionally inside dir, and returns its
+// output. Git is never allowed to wait for input: prompts for credentials or
+// SSH host keys fail immediately instead of hanging go-pwr.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	subcommand := args[0]
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = nonInteractiveEnv()
+	out, err := cmd.CombinedOutput()
+	if ctxErr := contextError(ctx, subcommand); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("git %s error: %v\n%s", subcommand, err, string(out))
+	}
+	return string(out), nil
+}
+
+// nonInteractiveEnv returns the environment for git commands with every
+// interactive prompt disabled.
+func nonInteractiveEnv() []string {
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",     // No username/password prompts
+		"GCM_INTERACTIVE=never",     // No Git Credential Manager dialogs
+		"SSH_ASKPASS_REQUIRE=never", // No graphical SSH passphrase prompts
+	)
+	// Fail instead of asking to trust unknown SSH host keys, unless the user
+	// has configured their own SSH command
+	if os.Getenv("GIT_SSH_COMMAND") == "" && os.Getenv("GIT_SSH") == "" {
+		env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	return env
+}
+
+// contextError reports why ctx ended, if it has, naming the operation
+// that was interrupted.
+func contextError(ctx context.Context, operation string) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return fmt.Errorf("git %s timed out", operation)
+	default:
+		return ctx.Err()
+	}
+}
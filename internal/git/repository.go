@@ -2,24 +2,70 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
 
 	"github.com/rocketpowerinc/go-pwr/internal/config"
 )
 
 // EnsureRepository ensures the script repository is cloned and up to date.
-func EnsureRepository(cfg *config.Config) error {
+// If the primary URL cannot be cloned, each configured mirror is tried in
+// order; cfg.ActiveRepoURL records the URL that was used. Each attempt is
+// bounded by cfg.GitTimeout, and cancelling ctx stops the sync and removes
+// any partial clone.
+func EnsureRepository(ctx context.Context, cfg *config.Config) error {
+	// Local directories are used as they are
+	if config.IsLocalSource(cfg.RepoURL) {
+		cfg.ScriptbinPath = config.RepositoryPath(cfg)
+		cfg.ActiveRepoURL = cfg.RepoURL
+		return nil
+	}
+
 	// Generate a unique path based on the repository URL
-	scriptPath := getRepositoryPath(cfg)
-	
-	// Always remove and re-clone for fresh content
-	if _, err := os.Stat(scriptPath); err == nil {
-		if err := os.RemoveAll(scriptPath); err != nil {
-			return fmt.Errorf("failed to remove old repository: %v", err)
+	scriptPath := config.RepositoryPath(cfg)
+	migrateLegacyClone(cfg, scriptPath)
+
+	sources := append([]string{cfg.RepoURL}, cfg.RepoMirrors...)
+
+	// Release archives are unpacked instead of cloned
+	if config.IsArchiveSource(cfg.RepoURL) {
+		return trySources(ctx, cfg, sources, scriptPath, func(ctx context.Context, source string) error {
+			return ensureArchive(ctx, source, cfg.RepoSHA256, scriptPath)
+		})
+	}
+
+	client, err := NewClient(cfg.GitClient)
+	if err != nil {
+		return err
+	}
+	depth, err := submoduleDepth(cfg.Submodules)
+	if err != nil {
+		return err
+	}
+
+	// Update an existing checkout of the primary repository in place. One
+	// from a mirror is only updated if the primary still fails, so go-pwr
+	// returns to the primary once it is back.
+	origin := originURL(scriptPath)
+	if origin != "" && origin == cfg.RepoURL {
+		updateCtx, cancel := withTimeout(ctx, cfg.GitTimeout)
+		err := update(updateCtx, client, scriptPath, depth)
+		cancel()
+		if err == nil {
+			cfg.ScriptbinPath = scriptPath
+			cfg.ActiveRepoURL = origin
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+		// Fall through and re-clone if the update failed
 	}
 
 	// Ensure parent directory exists
@@ -28,43 +74,172 @@ func EnsureRepository(cfg *config.Config) error {
 		return fmt.Errorf("failed to create parent directories: %v", err)
 	}
 
-	cmd := exec.Command("git", "clone", cfg.RepoURL, scriptPath)
-	out, err := cmd.CombinedOutput()
+	return trySources(ctx, cfg, sources, scriptPath, func(ctx context.Context, source string) error {
+		if source == origin && source != cfg.RepoURL {
+			if err := update(ctx, client, scriptPath, depth); err == nil || ctx.Err() != nil {
+				return err
+			}
+		}
+		return cloneInto(ctx, client, source, scriptPath, depth)
+	})
+}
+
+// cloneInto clones source next to dir and replaces dir with it once the
+// clone succeeded, so a failed attempt keeps the previous checkout.
+func cloneInto(ctx context.Context, client Client, source, dir string, submoduleDepth int) error {
+	partial := dir + ".partial"
+	if err := os.RemoveAll(partial); err != nil {
+		return fmt.Errorf("failed to remove old partial clone: %v", err)
+	}
+	err := client.Clone(ctx, source, partial)
+	if err == nil && submoduleDepth > 0 {
+		err = client.UpdateSubmodules(ctx, partial, submoduleDepth)
+	}
 	if err != nil {
-		return fmt.Errorf("git clone error: %v\n%s", err, string(out))
+		os.RemoveAll(partial) // Don't leave a partial clone behind
+		return err
 	}
 
-	// Update the config with the actual path used
-	cfg.ScriptbinPath = scriptPath
+	// Remove anything left at the path for fresh content
+	if err := os.RemoveAll(dir); err != nil {
+		os.RemoveAll(partial)
+		return fmt.Errorf("failed to remove old repository: %v", err)
+	}
+	if err := os.Rename(partial, dir); err != nil {
+		return fmt.Errorf("failed to move clone into place: %v", err)
+	}
 	return nil
 }
 
-// getRepositoryPath generates a path for the repository based on the URL
-func getRepositoryPath(cfg *config.Config) string {
-	// If it's the default repository, use the default scriptbin path
-	if cfg.RepoURL == config.GetDefaultRepoURL() {
-		// Get the original default scriptbin path from config
-		homeDir, err := os.UserHomeDir()
+// trySources calls fetch for each source in order until one succeeds and
+// records it in the config. A cancelled ctx stops without trying the rest.
+func trySources(ctx context.Context, cfg *config.Config, sources []string, scriptPath string, fetch func(context.Context, string) error) error {
+	var failures []string
+	for _, source := range sources {
+		attemptCtx, cancel := withTimeout(ctx, cfg.GitTimeout)
+		err := fetch(attemptCtx, source)
+		cancel()
 		if err != nil {
-			return cfg.ScriptbinPath // Fallback to current path
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", source, err))
+			continue
 		}
+
+		// Update the config with the actual path and source used
+		cfg.ScriptbinPath = scriptPath
+		cfg.ActiveRepoURL = source
+		return nil
+	}
+
+	if len(failures) == 1 {
+		return fmt.Errorf("%s", strings.TrimPrefix(failures[0], sources[0]+": "))
+	}
+	return fmt.Errorf("all sources failed:\n%s", strings.Join(failures, "\n"))
+}
+
+// withTimeout bounds ctx by timeout, if one is set.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// update fetches the remote HEAD and checks it out in an existing checkout,
+// bringing submodules along to the recorded commits.
+func update(ctx context.Context, client Client, dir string, submoduleDepth int) error {
+	rev, err := client.Fetch(ctx, dir)
+	if err != nil {
+		return err
+	}
+	if err := client.Checkout(ctx, dir, rev); err != nil {
+		return err
+	}
+	if submoduleDepth > 0 {
+		return client.UpdateSubmodules(ctx, dir, submoduleDepth)
+	}
+	return nil
+}
+
+// originURL returns the origin remote URL of the checkout in dir, or an
+// empty string if dir is not a git repository.
+func originURL(dir string) string {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return ""
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return remote.Config().URLs[0]
+}
+
+// migrateLegacyClone moves a clone of the configured repository from the
+// old ~/Downloads/Temp location into the cache directory.
+func migrateLegacyClone(cfg *config.Config, scriptPath string) {
+	legacyPath := legacyRepositoryPath(cfg.RepoURL)
+	if legacyPath == "" || originURL(legacyPath) != cfg.RepoURL {
+		return
+	}
+	if _, err := os.Stat(scriptPath); err == nil {
+		return // Already have a clone in the new location
+	}
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+		return
+	}
+	// A failed rename (e.g. across filesystems) just means a fresh clone
+	os.Rename(legacyPath, scriptPath)
+}
+
+// legacyRepositoryPath returns where versions before the cache directory
+// stored the clone of repoURL.
+func legacyRepositoryPath(repoURL string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	// The default repository used a fixed scriptbin directory
+	if repoURL == config.GetDefaultRepoURL() {
 		return filepath.Join(homeDir, "Downloads", "Temp", "scriptbin")
 	}
-	
-	// For custom repositories, create a unique directory name
-	// Extract repository name from URL (remove .git suffix)
-	repoName := filepath.Base(cfg.RepoURL)
+
+	// Custom repositories were named after the last URL path element
+	repoName := filepath.Base(repoURL)
 	if filepath.Ext(repoName) == ".git" {
 		repoName = repoName[:len(repoName)-4]
 	}
-	
-	// Use the Downloads/Temp directory for consistency
-	homeDir, err := os.UserHomeDir()
+	return filepath.Join(homeDir, "Downloads", "Temp", "custom-"+repoName)
+}
+
+// PruneCache removes local clones that do not belong to the configured
+// source and returns the removed paths. With dryRun set nothing is deleted.
+func PruneCache(cfg *config.Config, dryRun bool) ([]string, error) {
+	reposDir := config.RepositoriesDir(cfg)
+	entries, err := os.ReadDir(reposDir)
 	if err != nil {
-		// Fallback to using the parent directory of the current scriptbin path
-		parentDir := filepath.Dir(cfg.ScriptbinPath)
-		return filepath.Join(parentDir, "custom-"+repoName)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache directory: %v", err)
 	}
-	
-	return filepath.Join(homeDir, "Downloads", "Temp", "custom-"+repoName)
+
+	inUse := config.RepoHash(cfg.RepoURL)
+	var pruned []string
+	for _, entry := range entries {
+		if entry.Name() == inUse {
+			continue
+		}
+		path := filepath.Join(reposDir, entry.Name())
+		if !dryRun {
+			if err := os.RemoveAll(path); err != nil {
+				return pruned, fmt.Errorf("failed to remove %s: %v", path, err)
+			}
+		}
+		pruned = append(pruned, path)
+	}
+	return pruned, nil
 }
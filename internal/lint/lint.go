@@ -0,0 +1,382 @@
+// Package lint validates scriptbin repositories for script authors.
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// Severity levels for lint issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue describes a single problem found in a repository.
+type Issue struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+}
+
+// Report holds the result of linting a repository.
+type Report struct {
+	Root     string  `json:"root"`
+	Scripts  int     `json:"scripts"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+	Issues   []Issue `json:"issues"`
+}
+
+// headerLines is how far into a file tag headers are looked for.
+const headerLines = 50
+
+var (
+	tagsHeaderPattern = regexp.MustCompile(`^#\*Tags:?\s*$`)
+	looseTagsPattern  = regexp.MustCompile(`(?i)^#\s*\*?\s*tags\s*:?\s*$`)
+	categoryPattern   = regexp.MustCompile(`^#\s*([A-Za-z_]+):\s*(.+)$`)
+	legacyPattern     = regexp.MustCompile(`^#([a-zA-Z_]+)\s+#([a-zA-Z_]+)`)
+	paramPattern      = regexp.MustCompile(`(?i)^#!?\s*param:\s*(.*)$`)
+	requiresPattern   = regexp.MustCompile(`(?i)^#!?\s*requires:\s*(.*)$`)
+)
+
+// Run lints every file below root.
+func Run(root string) (*Report, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Root: root, Issues: []Issue{}}
+	byName := make(map[string][]string)
+
+	err = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if name == ".git" {
+				return filepath.SkipDir
+			}
+			checkManifest(report, root, path)
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if hidden := hiddenComponent(rel); hidden != "" {
+			// go-pwr never lists anything below a hidden file or directory
+			if scripts.IsScriptPath(path) {
+				report.add(rel, 0, SeverityWarning, "skipped", "script is inside hidden path "+hidden+" and will not be listed")
+			}
+			return nil
+		}
+
+		if !scripts.IsScriptPath(path) {
+			checkUnlisted(report, path, rel)
+			return nil
+		}
+
+		report.Scripts++
+		byName[strings.ToLower(name)] = append(byName[strings.ToLower(name)], rel)
+		checkScript(report, path, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	checkDuplicates(report, byName)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Path != report.Issues[j].Path {
+			return report.Issues[i].Path < report.Issues[j].Path
+		}
+		return report.Issues[i].Line < report.Issues[j].Line
+	})
+	return report, nil
+}
+
+// HasFailures reports whether the report should fail a build. With strict
+// set, warnings count as failures too.
+func (r *Report) HasFailures(strict bool) bool {
+	return r.Errors > 0 || (strict && r.Warnings > 0)
+}
+
+// add records an issue and updates the counters.
+func (r *Report) add(path string, line int, severity, check, message string) {
+	r.Issues = append(r.Issues, Issue{
+		Path:     filepath.ToSlash(path),
+		Line:     line,
+		Severity: severity,
+		Check:    check,
+		Message:  message,
+	})
+	if severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// checkScript runs the per-file checks on a supported script.
+func checkScript(report *Report, path, rel string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		report.add(rel, 0, SeverityError, "unreadable", err.Error())
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	isShell := ext == ".sh" || ext == ".bash"
+	if isShell {
+		if !bytes.HasPrefix(data, []byte("#!")) {
+			report.add(rel, 1, SeverityError, "shebang", "shell script has no shebang line (e.g. #!/usr/bin/env bash)")
+		}
+		if bytes.Contains(data, []byte("\r\n")) {
+			report.add(rel, 0, SeverityError, "crlf", "shell script has CRLF line endings; bash will fail on the carriage returns")
+		}
+		if runtime.GOOS != "windows" {
+			if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0111 == 0 {
+				report.add(rel, 0, SeverityWarning, "executable", "shell script is not executable (chmod +x)")
+			}
+		}
+	}
+
+	checkTags(report, path, data, rel)
+}
+
+// checkTags validates the tag header of a script, reading comments in the
+// script's own syntax.
+func checkTags(report *Report, path string, data []byte, rel string) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for len(lines) < headerLines && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	lines = scripts.NormalizeHeader(path, lines)
+
+	inTags := false
+	headerLine := 0
+	categories := 0
+
+	frontMatter := scripts.FindFrontMatter(lines)
+	if frontMatter != nil {
+		checkFrontMatter(report, frontMatter, rel)
+	}
+
+	for i, line := range lines {
+		lineNo := i + 1
+		line = strings.TrimSpace(line)
+		if frontMatter != nil && i >= frontMatter.Start && (frontMatter.End < 0 || i <= frontMatter.End) {
+			continue
+		}
+
+		if matches := paramPattern.FindStringSubmatch(line); matches != nil {
+			if _, err := scripts.ParseParam(matches[1]); err != nil {
+				report.add(rel, lineNo, SeverityError, "param", "invalid parameter declaration: "+err.Error())
+			}
+			continue
+		}
+
+		if matches := requiresPattern.FindStringSubmatch(line); matches != nil {
+			for _, spec := range strings.FieldsFunc(matches[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+				if _, err := platform.ParseRequirement(spec); err != nil {
+					report.add(rel, lineNo, SeverityError, "requires", err.Error())
+				}
+			}
+			continue
+		}
+
+		if tagsHeaderPattern.MatchString(line) {
+			if headerLine != 0 {
+				report.add(rel, lineNo, SeverityError, "tags-header", "duplicate #*Tags: header; only the first one is read")
+				continue
+			}
+			inTags = true
+			headerLine = lineNo
+			continue
+		}
+
+		if !inTags {
+			if looseTagsPattern.MatchString(line) {
+				report.add(rel, lineNo, SeverityError, "tags-header", "tags header must be written exactly as #*Tags:")
+			} else if legacyPattern.MatchString(line) {
+				report.add(rel, lineNo, SeverityWarning, "legacy-tags", "legacy #tag #tag format; move these into a #*Tags: block")
+			}
+			continue
+		}
+
+		// Inside the tags block: a blank or non-comment line ends it
+		if line == "" || !strings.HasPrefix(line, "#") {
+			inTags = false
+			continue
+		}
+
+		if scripts.IsHeaderField(line) {
+			continue
+		}
+		matches := categoryPattern.FindStringSubmatch(line)
+		if matches == nil {
+			report.add(rel, lineNo, SeverityError, "tags-syntax", "malformed tag line; expected \"# Category: value value\"")
+			continue
+		}
+		categories++
+		if !scripts.IsKnownCategory(matches[1]) {
+			report.add(rel, lineNo, SeverityWarning, "unknown-category", "unknown tag category \""+matches[1]+"\" (known: "+strings.Join(scripts.KnownCategories, ", ")+")")
+		}
+	}
+
+	if headerLine != 0 && categories == 0 {
+		report.add(rel, headerLine, SeverityError, "tags-empty", "#*Tags: header is not followed by any \"# Category: values\" lines")
+	}
+}
+
+// checkFrontMatter validates the YAML front matter of a script.
+func checkFrontMatter(report *Report, frontMatter *scripts.FrontMatter, rel string) {
+	parsed := &scripts.ScriptTags{}
+	if err := frontMatter.Apply(parsed); err != nil {
+		report.add(rel, frontMatter.Start+1, SeverityError, "front-matter", err.Error())
+		return
+	}
+	reported := make(map[string]bool)
+	for _, tag := range parsed.Tags {
+		if !scripts.IsKnownCategory(tag.Category) && !reported[tag.Category] {
+			reported[tag.Category] = true
+			report.add(rel, frontMatter.Start+1, SeverityWarning, "unknown-category", "unknown tag category \""+tag.Category+"\" (known: "+strings.Join(scripts.KnownCategories, ", ")+")")
+		}
+	}
+}
+
+// checkManifest validates the manifest of dir, if it has one.
+func checkManifest(report *Report, root, dir string) {
+	manifest, err := scripts.LoadManifest(dir)
+	if manifest == nil && err == nil {
+		return
+	}
+	if err != nil {
+		for _, name := range scripts.ManifestFiles {
+			if _, statErr := os.Stat(filepath.Join(dir, name)); statErr == nil {
+				rel, _ := filepath.Rel(root, filepath.Join(dir, name))
+				report.add(rel, 0, SeverityError, "manifest", err.Error())
+				return
+			}
+		}
+		return
+	}
+
+	rel, _ := filepath.Rel(root, manifest.Path)
+	if dir != root && (len(manifest.Synonyms) > 0 || len(manifest.Parents) > 0) {
+		report.add(rel, 0, SeverityWarning, "manifest", "synonyms and parents are only read from the manifest at the repository root")
+	}
+	if dir != root && len(manifest.Workflows) > 0 {
+		report.add(rel, 0, SeverityWarning, "workflow", "workflows are only read from the manifest at the repository root")
+	}
+	if dir == root {
+		checkWorkflows(report, root, manifest, rel)
+	}
+	names := make([]string, 0, len(manifest.Scripts))
+	for name := range manifest.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			report.add(rel, 0, SeverityWarning, "manifest", "entry \""+name+"\" does not match any file in "+filepath.Dir(filepath.ToSlash(rel)))
+			continue
+		}
+		for category := range manifest.Scripts[name].Tags {
+			if !scripts.IsKnownCategory(category) {
+				report.add(rel, 0, SeverityWarning, "unknown-category", "entry \""+name+"\" uses unknown tag category \""+category+"\"")
+			}
+		}
+	}
+}
+
+// checkWorkflows reports workflow steps that name no script or whose
+// parameters do not validate.
+func checkWorkflows(report *Report, root string, manifest *scripts.Manifest, rel string) {
+	names := make([]string, 0, len(manifest.Workflows))
+	for name := range manifest.Workflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		workflow := manifest.Workflows[name]
+		workflow.Name = name
+		if _, err := workflow.Plan(root); err != nil {
+			for _, problem := range strings.Split(err.Error(), "\n") {
+				report.add(rel, 0, SeverityError, "workflow", "workflow \""+name+"\": "+problem)
+			}
+		}
+	}
+}
+
+// checkUnlisted warns about files that look like scripts but have an
+// extension go-pwr does not list.
+func checkUnlisted(report *Report, path, rel string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan() && lineNo <= headerLines; lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if (lineNo == 1 && strings.HasPrefix(line, "#!")) || tagsHeaderPattern.MatchString(line) {
+			report.add(rel, 0, SeverityWarning, "skipped", "file looks like a script but its extension is not supported, so it will not be listed")
+			return
+		}
+	}
+}
+
+// checkDuplicates warns about scripts that share a file name.
+func checkDuplicates(report *Report, byName map[string][]string) {
+	for _, paths := range byName {
+		if len(paths) < 2 {
+			continue
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			report.add(path, 0, SeverityWarning, "duplicate-name", "script name is also used by "+strings.Join(otherPaths(paths, path), ", "))
+		}
+	}
+}
+
+// otherPaths returns paths without path.
+func otherPaths(paths []string, path string) []string {
+	var others []string
+	for _, p := range paths {
+		if p != path {
+			others = append(others, filepath.ToSlash(p))
+		}
+	}
+	return others
+}
+
+// hiddenComponent returns the first hidden element of a relative path.
+func hiddenComponent(rel string) string {
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") {
+			return part
+		}
+	}
+	return ""
+}
@@ -0,0 +1,191 @@
+package scripts
+
+import (
+	"container/list"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Default limits of the preview cache.
+const (
+	DefaultCacheEntries = 256
+	DefaultCacheBytes   = 32 << 20
+)
+
+// Cache provides thread-safe caching for rendered script contents. It holds
+// at most a fixed number of entries and bytes, evicting the least recently
+// used, and drops entries whose file changed since they were cached.
+type Cache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // Front is the most recently used
+	bytes      int
+	maxEntries int
+	maxBytes   int
+	stats      CacheStats
+}
+
+// cacheEntry is a cached rendering of one file.
+type cacheEntry struct {
+	key     string
+	path    string
+	content string
+	modTime int64
+	size    int64
+}
+
+// CacheStats counts how well the cache is doing.
+type CacheStats struct {
+	Hits          int `json:"hits"`
+	Misses        int `json:"misses"`
+	Evictions     int `json:"evictions"`     // Dropped to stay within the limits
+	Invalidations int `json:"invalidations"` // Dropped because the file changed
+	Entries       int `json:"entries"`
+	Bytes         int `json:"bytes"`
+}
+
+// NewCache creates a new script cache with the default limits.
+func NewCache() *Cache {
+	return NewCacheWithLimits(DefaultCacheEntries, DefaultCacheBytes)
+}
+
+// NewCacheWithLimits creates a script cache holding at most maxEntries
+// entries and maxBytes bytes of content.
+func NewCacheWithLimits(maxEntries, maxBytes int) *Cache {
+	return &Cache{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+}
+
+// cacheKey combines a path with the options it was rendered with, such as
+// the bat theme, so different renderings don't overwrite each other.
+func cacheKey(path, variant string) string {
+	return path + "\x00" + variant
+}
+
+// Get retrieves the content of path rendered as variant, if it is cached
+// and the file has not changed since.
+func (sc *Cache) Get(path, variant string) (string, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	element, ok := sc.entries[cacheKey(path, variant)]
+	if !ok {
+		sc.stats.Misses++
+		return "", false
+	}
+	entry := element.Value.(*cacheEntry)
+	if info, err := os.Stat(path); err != nil || info.ModTime().UnixNano() != entry.modTime || info.Size() != entry.size {
+		sc.remove(element)
+		sc.stats.Invalidations++
+		sc.stats.Misses++
+		return "", false
+	}
+
+	sc.lru.MoveToFront(element)
+	sc.stats.Hits++
+	return entry.content, true
+}
+
+// Set stores the content of path rendered as variant. Errors should not be
+// stored, so that they are retried the next time.
+func (sc *Cache) Set(path, variant, content string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return // Nothing to validate the entry against later
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	key := cacheKey(path, variant)
+	if element, ok := sc.entries[key]; ok {
+		sc.remove(element)
+	}
+	if len(content) > sc.maxBytes {
+		return // Would evict everything else and still not fit
+	}
+
+	entry := &cacheEntry{key: key, path: path, content: content, modTime: info.ModTime().UnixNano(), size: info.Size()}
+	sc.entries[key] = sc.lru.PushFront(entry)
+	sc.bytes += len(content)
+
+	for len(sc.entries) > sc.maxEntries || sc.bytes > sc.maxBytes {
+		sc.remove(sc.lru.Back())
+		sc.stats.Evictions++
+	}
+}
+
+// Invalidate drops the cached content of path and of anything below it.
+func (sc *Cache) Invalidate(path string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	prefix := path + string(filepath.Separator)
+	for _, element := range sc.entries {
+		if cached := element.Value.(*cacheEntry).path; cached == path || strings.HasPrefix(cached, prefix) {
+			sc.remove(element)
+			sc.stats.Invalidations++
+		}
+	}
+}
+
+// Clear empties the cache.
+func (sc *Cache) Clear() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.entries = make(map[string]*list.Element)
+	sc.lru.Init()
+	sc.bytes = 0
+}
+
+// Stats returns the hit and miss counts and the current size of the cache.
+func (sc *Cache) Stats() CacheStats {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	stats := sc.stats
+	stats.Entries = len(sc.entries)
+	stats.Bytes = sc.bytes
+	return stats
+}
+
+// remove drops an entry. The caller holds the lock.
+func (sc *Cache) remove(element *list.Element) {
+	entry := sc.lru.Remove(element).(*cacheEntry)
+	delete(sc.entries, entry.key)
+	sc.bytes -= len(entry.content)
+}
+
+// CacheStatsPath returns where the preview cache statistics of the last
+// session are kept under the cache directory.
+func CacheStatsPath(cacheDir string) string {
+	return filepath.Join(cacheDir, "preview-stats.json")
+}
+
+// SaveCacheStats writes cache statistics to file.
+func SaveCacheStats(file string, stats CacheStats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// LoadCacheStats reads cache statistics written by SaveCacheStats.
+func LoadCacheStats(file string) (CacheStats, error) {
+	var stats CacheStats
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return stats, err
+	}
+	err = json.Unmarshal(data, &stats)
+	return stats, err
+}
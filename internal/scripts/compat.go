@@ -0,0 +1,167 @@
+package scripts
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// platformAliases maps Platforms tag values to the GOOS they describe.
+// "wsl" is handled separately since it is Linux with an extra condition.
+var platformAliases = map[string]string{
+	"linux":   "linux",
+	"mac":     "darwin",
+	"macos":   "darwin",
+	"osx":     "darwin",
+	"darwin":  "darwin",
+	"windows": "windows",
+	"win":     "windows",
+	"freebsd": "freebsd",
+}
+
+// archAliases maps Architectures tag values to GOARCH.
+var archAliases = map[string]string{
+	"amd64":   "amd64",
+	"x86_64":  "amd64",
+	"x64":     "amd64",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"arm":     "arm",
+	"armv7":   "arm",
+	"armhf":   "arm",
+	"386":     "386",
+	"x86":     "386",
+	"i386":    "386",
+	"i686":    "386",
+}
+
+// Incompatibility returns why a script cannot run on the host described by
+// facts, or an empty string if nothing in its tags rules the host out.
+// Within a category any one matching value is enough; values go-pwr does
+// not recognise are ignored rather than hiding the script.
+func Incompatibility(tags *ScriptTags, facts platform.Facts) string {
+	if tags == nil {
+		return ""
+	}
+
+	if values := tags.authoredTags("platforms"); len(values) > 0 {
+		known, match := false, false
+		for _, value := range values {
+			if value == "wsl" {
+				known = true
+				match = match || facts.WSL
+				continue
+			}
+			if goos, ok := platformAliases[value]; ok {
+				known = true
+				match = match || goos == facts.OS
+			}
+		}
+		if known && !match {
+			return "needs " + strings.Join(values, "/")
+		}
+	}
+
+	if values := tags.authoredTags("architectures"); len(values) > 0 {
+		known, match := false, false
+		for _, value := range values {
+			if arch, ok := archAliases[value]; ok {
+				known = true
+				match = match || arch == facts.Arch
+			}
+		}
+		if known && !match {
+			return "needs " + strings.Join(values, "/")
+		}
+	}
+
+	// Distros only say something about Linux hosts whose distro is known
+	if values := tags.authoredTags("distros"); len(values) > 0 && facts.OS == "linux" && facts.Distro != "" {
+		match := false
+		for _, value := range values {
+			if value == facts.Distro {
+				match = true
+			}
+			for _, like := range facts.DistroLike {
+				match = match || value == like
+			}
+		}
+		if !match {
+			return "needs " + strings.Join(values, "/")
+		}
+	}
+
+	if values := tags.authoredTags("packagemanagers"); len(values) > 0 {
+		known, match := false, false
+		for _, value := range values {
+			if isKnownPackageManager(value) {
+				known = true
+				match = match || facts.HasPackageManager(value)
+			}
+		}
+		if known && !match {
+			return "needs " + strings.Join(values, "/")
+		}
+	}
+	return ""
+}
+
+// isKnownPackageManager reports whether go-pwr looks for the package manager.
+func isKnownPackageManager(name string) bool {
+	for _, manager := range platform.KnownPackageManagers {
+		if manager == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withCompatibility records whether a script can run on this host and
+// which of its requirements are missing. Versions are not probed here, so
+// listing stays fast; see platform.ProbeRequirements.
+func withCompatibility(item Item) Item {
+	item.incompatible = Incompatibility(item.tags, platform.HostFacts())
+	item.unmet = platform.CheckRequirementsQuickly(item.Requires())
+	return item
+}
+
+// FilterCompatible removes scripts that cannot run on this host, keeping
+// directories.
+func FilterCompatible(items []list.Item) []list.Item {
+	filtered := make([]list.Item, 0, len(items))
+	for _, item := range items {
+		if scriptItem, ok := item.(Item); ok && scriptItem.IsScript() && !scriptItem.IsCompatible() {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// Privilege levels a script can require.
+const (
+	PrivilegeAdmin = "admin" // Must run with administrator rights
+	PrivilegeUser  = "user"  // Must not run as root
+)
+
+// Privilege returns the privilege level the Privilege tags demand, or an
+// empty string when the script runs either way (e.g. "Admin User").
+func (st *ScriptTags) Privilege() string {
+	admin, user := false, false
+	for _, value := range st.GetTagsByCategory("privilege") {
+		switch value {
+		case "admin", "administrator", "root", "sudo", "elevated":
+			admin = true
+		case "user", "standard", "nonroot", "non-root":
+			user = true
+		}
+	}
+	switch {
+	case admin && !user:
+		return PrivilegeAdmin
+	case user && !admin:
+		return PrivilegeUser
+	}
+	return ""
+}
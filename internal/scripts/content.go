@@ -0,0 +1,83 @@
+package scripts
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// maxContentMatches caps the lines reported per script by a content search.
+const maxContentMatches = 20
+
+// contentContext is how many lines around a match are kept for display.
+const contentContext = 1
+
+// ContentMatch is a line of a script that matched a content search.
+type ContentMatch struct {
+	Line   int      // Line number, counted from 1
+	Text   string   // The matching line
+	Before []string // Context lines above the match
+	After  []string // Context lines below the match
+}
+
+// Matches returns the lines a content search found in the script.
+func (s Item) Matches() []ContentMatch {
+	return s.matches
+}
+
+// SearchContent returns the lines of a file containing term, ignoring case.
+func SearchContent(path, term string) []ContentMatch {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	var matches []ContentMatch
+	for i, line := range lines {
+		if !strings.Contains(strings.ToLower(line), term) {
+			continue
+		}
+		match := ContentMatch{
+			Line:   i + 1,
+			Text:   line,
+			Before: lines[max(i-contentContext, 0):i],
+			After:  lines[i+1 : min(i+1+contentContext, len(lines))],
+		}
+		matches = append(matches, match)
+		if len(matches) == maxContentMatches {
+			break
+		}
+	}
+	return matches
+}
+
+// FilterItemsByContent keeps the scripts whose bodies contain term,
+// recording the matching lines on each item. Directories are dropped.
+func FilterItemsByContent(items []list.Item, term string) []list.Item {
+	var filteredItems []list.Item
+	for _, item := range items {
+		scriptItem, ok := item.(Item)
+		if !ok || !scriptItem.IsScript() {
+			continue
+		}
+		if matches := SearchContent(scriptItem.path, term); len(matches) > 0 {
+			scriptItem.matches = matches
+			filteredItems = append(filteredItems, scriptItem)
+		}
+	}
+	return filteredItems
+}
@@ -0,0 +1,57 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// FavoritesFolder names the virtual folder pinned scripts are listed in at
+// the repository root.
+const FavoritesFolder = "★ Favorites"
+
+// FavoritesPath returns the path of the virtual favorites folder below
+// root. Nothing exists there; it only identifies the folder in listings.
+func FavoritesPath(root string) string {
+	return filepath.Join(root, FavoritesFolder)
+}
+
+// FavoritesItem returns the directory item of the virtual favorites folder.
+func FavoritesItem(root string) Item {
+	return Item{name: FavoritesFolder + "/", path: FavoritesPath(root)}
+}
+
+// RelativePath returns the slash-separated path of a script below root,
+// the form pins are stored in so they survive the repository being cloned
+// again.
+func RelativePath(root, path string) (string, bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// Favorites loads the pinned scripts below root in the order they were
+// pinned, named by their path in the repository. Pins whose script no
+// longer exists, e.g. after it was removed upstream, are left out.
+func Favorites(root string, pins []string) []list.Item {
+	items := make([]list.Item, 0, len(pins))
+	for _, pin := range pins {
+		path := filepath.Join(root, filepath.FromSlash(pin))
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		item, err := LoadItem(path)
+		if err != nil {
+			continue
+		}
+		if dir := filepath.Dir(filepath.FromSlash(pin)); dir != "." {
+			item.name = filepath.Join(dir, item.name) // As recursive listings show it
+		}
+		items = append(items, item)
+	}
+	return items
+}
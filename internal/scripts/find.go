@@ -0,0 +1,117 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindScript resolves a script argument: a script of the repository
+// matched by relative path, file name (with or without its extension) or
+// alias, or a file outside it named by an absolute path or one starting
+// with ./ or ../.
+func FindScript(root, name string) (Item, error) {
+	if isFilePath(name) {
+		return loadScriptFile(name)
+	}
+	return findScriptIn(allScripts(root), root, name)
+}
+
+// isFilePath reports whether name is a path to a file rather than the name
+// of a script of the repository. Other relative names are looked up in the
+// repository, so a file of the same name in the working directory cannot
+// take a script's place.
+func isFilePath(name string) bool {
+	if filepath.IsAbs(name) {
+		return true
+	}
+	name = filepath.ToSlash(name)
+	return strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../")
+}
+
+// loadRepositoryFile loads the script at a path relative to root, such as
+// a workflow step's "./linux/update.sh". Paths leading out of the
+// repository, also through symbolic links, are refused, so a synced
+// repository cannot point at other files on the host.
+func loadRepositoryFile(root, path string) (Item, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return Item{}, fmt.Errorf("failed to resolve %s: %v", root, err)
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return Item{}, fmt.Errorf("no script at %s", path)
+	}
+	if _, ok := RelativePath(realRoot, realPath); !ok {
+		return Item{}, fmt.Errorf("%s is outside the repository", path)
+	}
+	return loadScriptFile(path)
+}
+
+// loadScriptFile loads the script at path.
+func loadScriptFile(path string) (Item, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Item{}, fmt.Errorf("no script at %s", path)
+	}
+	if info.IsDir() {
+		return Item{}, fmt.Errorf("%s is a directory", path)
+	}
+	return LoadItem(path)
+}
+
+// allScripts lists every script below root.
+func allScripts(root string) []Item {
+	var items []Item
+	for _, listItem := range GetAllScriptsRecursively(root) {
+		items = append(items, listItem.(Item))
+	}
+	return items
+}
+
+// findScriptIn resolves name among the scripts of the repository at root.
+func findScriptIn(items []Item, root, name string) (Item, error) {
+	var matches []Item
+	for _, item := range items {
+		if scriptMatches(item, root, name) {
+			matches = append(matches, item)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Item{}, fmt.Errorf("no script named %q in %s", name, root)
+	case 1:
+		return matches[0], nil
+	default:
+		var paths []string
+		for _, item := range matches {
+			rel, _ := filepath.Rel(root, item.Description())
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return Item{}, fmt.Errorf("%q is ambiguous, use one of: %s", name, strings.Join(paths, ", "))
+	}
+}
+
+// scriptMatches reports whether name refers to item.
+func scriptMatches(item Item, root, name string) bool {
+	rel, _ := filepath.Rel(root, item.Description())
+	base := filepath.Base(item.Description())
+	candidates := append([]string{
+		filepath.ToSlash(rel),
+		base,
+		strings.TrimSuffix(base, filepath.Ext(base)),
+		item.Title(),
+	}, item.Aliases()...)
+
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, filepath.ToSlash(name)) {
+			return true
+		}
+	}
+	return false
+}
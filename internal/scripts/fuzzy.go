@@ -0,0 +1,51 @@
+package scripts
+
+import (
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/sahilm/fuzzy"
+)
+
+// fuzzySource lets the fuzzy matcher rank scripts by their titles, which
+// are relative paths in recursive listings.
+type fuzzySource []Item
+
+func (fs fuzzySource) String(i int) string { return fs[i].Title() }
+func (fs fuzzySource) Len() int            { return len(fs) }
+
+// MatchedRunes returns the positions of the title characters a fuzzy search
+// matched, counted in runes.
+func (s Item) MatchedRunes() []int {
+	return s.matchedRunes
+}
+
+// FilterItemsByFuzzy keeps the scripts whose titles fuzzily match pattern,
+// best match first, recording the matched characters on each item.
+// Directories are dropped.
+func FilterItemsByFuzzy(items []list.Item, pattern string) []list.Item {
+	var source fuzzySource
+	for _, item := range items {
+		if scriptItem, ok := item.(Item); ok && scriptItem.IsScript() {
+			source = append(source, scriptItem)
+		}
+	}
+
+	matches := fuzzy.FindFrom(pattern, source)
+	filteredItems := make([]list.Item, 0, len(matches))
+	for _, match := range matches {
+		scriptItem := source[match.Index]
+		scriptItem.matchedRunes = runeIndexes(match.Str, match.MatchedIndexes)
+		filteredItems = append(filteredItems, scriptItem)
+	}
+	return filteredItems
+}
+
+// runeIndexes converts byte offsets into str to rune positions.
+func runeIndexes(str string, byteIndexes []int) []int {
+	runes := make([]int, 0, len(byteIndexes))
+	for _, index := range byteIndexes {
+		runes = append(runes, utf8.RuneCountInString(str[:index]))
+	}
+	return runes
+}
@@ -0,0 +1,262 @@
+package scripts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+	"gopkg.in/yaml.v3"
+)
+
+// NormalizeHeader rewrites the comments of a script written in any
+// supported language into the "#" form the header parser reads, one output
+// line per input line so line numbers stay valid:
+//
+//	REM Platforms: windows     ->  #Platforms: windows
+//	// Platforms: linux        ->  #Platforms: linux
+//	<# ... #>, /* ... */       ->  each inner line prefixed with #
+//
+// A line that only opens or closes a block comment, and a blank line inside
+// one, becomes empty. Code lines are returned unchanged.
+func NormalizeHeader(path string, lines []string) []string {
+	interp, ok := platform.ResolveInterpreter(path)
+	if !ok {
+		return lines
+	}
+	markers := interp.CommentMarkers()
+	open, close := interp.BlockComment[0], interp.BlockComment[1]
+	if len(markers) == 1 && markers[0] == "#" && open == "" {
+		return lines
+	}
+
+	normalized := make([]string, len(lines))
+	inBlock := false
+	indent := -1 // Indentation of the current block comment's text
+	for i, raw := range lines {
+		if inBlock {
+			text := strings.TrimRight(raw, " \t\r")
+			if end := strings.Index(text, close); end >= 0 {
+				inBlock = false
+				text = text[:end]
+			}
+			if open == "/*" {
+				text = strings.TrimPrefix(strings.TrimLeft(text, " \t"), "*")
+			}
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			if indent < 0 {
+				indent = len(text) - len(strings.TrimLeft(text, " \t"))
+			}
+			if text = trimIndent(text, indent); !strings.HasPrefix(strings.TrimSpace(text), "#") {
+				text = "#" + text
+			}
+			normalized[i] = text
+			continue
+		}
+
+		line := strings.TrimSpace(raw)
+		if open != "" && strings.HasPrefix(line, open) {
+			text := line[len(open):]
+			if end := strings.Index(text, close); end >= 0 {
+				text = text[:end]
+			} else {
+				inBlock = true
+				indent = -1
+			}
+			if open == "/*" {
+				text = strings.TrimLeft(text, "*") // JSDoc's /**
+			}
+			if text = strings.TrimSpace(text); text != "" {
+				normalized[i] = "#" + text
+			}
+			continue
+		}
+
+		normalized[i] = raw
+		for _, marker := range markers {
+			if marker == "#" {
+				continue // Already in the form the parser reads
+			}
+			if text, ok := cutCommentMarker(line, marker); ok {
+				normalized[i] = "#" + strings.TrimPrefix(text, " ")
+				break
+			}
+		}
+	}
+	return normalized
+}
+
+// cutCommentMarker returns the text after a line comment marker. Word
+// markers such as REM ignore case and must be followed by a space, so a
+// command named REMOVE is not mistaken for a comment.
+func cutCommentMarker(line, marker string) (string, bool) {
+	if len(line) < len(marker) || !strings.EqualFold(line[:len(marker)], marker) {
+		return "", false
+	}
+	text := line[len(marker):]
+	if unicode.IsLetter(rune(marker[len(marker)-1])) && text != "" && text[0] != ' ' && text[0] != '\t' {
+		return "", false
+	}
+	return text, true
+}
+
+// trimIndent removes up to n leading spaces or tabs.
+func trimIndent(text string, n int) string {
+	for i := 0; i < n && text != "" && (text[0] == ' ' || text[0] == '\t'); i++ {
+		text = text[1:]
+	}
+	return text
+}
+
+// FrontMatter is a YAML block between "---" comment lines at the top of a
+// script header, an alternative to the #*Tags: block:
+//
+//	# ---
+//	# description: Installs developer tools
+//	# requires: [git, curl]
+//	# tags:
+//	#   platforms: [linux, mac]
+//	# ---
+type FrontMatter struct {
+	Start int // Index of the opening "---" line
+	End   int // Index of the closing "---" line, -1 if it is missing
+	Text  string
+}
+
+// FindFrontMatter returns the front matter of normalized header lines, or
+// nil if the first comment after the shebang is not "---".
+func FindFrontMatter(lines []string) *FrontMatter {
+	start := -1
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if i >= headerLines {
+			return nil
+		}
+		if line == "" || (i == 0 && strings.HasPrefix(line, "#!")) {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") || strings.TrimSpace(line[1:]) != "---" {
+			return nil
+		}
+		start = i
+		break
+	}
+	if start < 0 {
+		return nil
+	}
+
+	fm := &FrontMatter{Start: start, End: -1}
+	var body []string
+	for i := start + 1; i < len(lines) && i < headerLines; i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "#") && strings.TrimSpace(line[1:]) == "---" {
+			fm.End = i
+			break
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break // Code before the closing line
+		}
+		body = append(body, strings.TrimRight(strings.TrimPrefix(line, "#"), " \t\r"))
+	}
+
+	// Drop the indentation every line shares, usually the space after "#"
+	indent := -1
+	for _, line := range body {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" {
+			if n := len(line) - len(trimmed); indent < 0 || n < indent {
+				indent = n
+			}
+		}
+	}
+	for i := range body {
+		body[i] = trimIndent(body[i], indent)
+	}
+	fm.Text = strings.Join(body, "\n")
+	return fm
+}
+
+// Apply stores the front matter's fields in st. Descriptive fields use the
+// same names as header lines; a "tags" mapping and any other key become tag
+// categories.
+func (fm *FrontMatter) Apply(st *ScriptTags) error {
+	if fm.End < 0 {
+		return fmt.Errorf("front matter opened on line %d is not closed with ---", fm.Start+1)
+	}
+	var fields map[string]any
+	if err := yaml.Unmarshal([]byte(fm.Text), &fields); err != nil {
+		return fmt.Errorf("invalid front matter: %v", err)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Keep tag order stable between runs
+
+	for _, key := range keys {
+		name, value := strings.ToLower(key), fields[key]
+		switch {
+		case name == "tags":
+			categories, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("front matter tags must map categories to values")
+			}
+			names := make([]string, 0, len(categories))
+			for category := range categories {
+				names = append(names, category)
+			}
+			sort.Strings(names)
+			for _, category := range names {
+				st.addTagValues(category, frontMatterValues(categories[category]))
+			}
+		case name == "param" || name == "params":
+			if param, ok := value.(string); ok {
+				st.setMetadata("param", param)
+				continue
+			}
+			for _, param := range frontMatterValues(value) {
+				st.setMetadata("param", param)
+			}
+		case metadataPattern.MatchString("# " + name + ": x"):
+			if name == "requires" {
+				st.setMetadata(name, strings.Join(frontMatterValues(value), " "))
+			} else {
+				st.setMetadata(name, fmt.Sprint(value))
+			}
+		default:
+			st.addTagValues(name, frontMatterValues(value))
+		}
+	}
+	return nil
+}
+
+// frontMatterValues flattens a front matter value into strings: a list, or
+// a scalar split on whitespace the way tag lines are.
+func frontMatterValues(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		var values []string
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	case map[string]any:
+		return nil
+	}
+	return strings.Fields(fmt.Sprint(value))
+}
+
+// addTagValues appends tags to a category, lower-cased as tag lines are.
+func (st *ScriptTags) addTagValues(category string, values []string) {
+	for _, value := range values {
+		value = strings.Trim(value, ",")
+		if value != "" {
+			st.Tags = append(st.Tags, Tag{Category: strings.ToLower(category), Value: strings.ToLower(value)})
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTagsHeaderStyles(t *testing.T) {
+	tests := []struct {
+		file     string
+		longLine bool // Append a line longer than bufio.Scanner's 64 KB limit
+		want     ScriptTags
+	}{
+		{
+			file: "rem.bat",
+			want: ScriptTags{
+				Tags:        []Tag{{Category: "platforms", Value: "windows"}, {Category: "categories", Value: "setup"}, {Category: "categories", Value: "tools"}},
+				Description: "Installs tools with winget",
+			},
+		},
+		{
+			file: "colons.cmd",
+			want: ScriptTags{
+				Tags: []Tag{{Category: "platforms", Value: "windows"}, {Category: "privilege", Value: "admin"}},
+			},
+		},
+		{
+			file: "slashes.js",
+			want: ScriptTags{
+				Tags:   []Tag{{Category: "platforms", Value: "linux"}, {Category: "platforms", Value: "macos"}, {Category: "languages", Value: "javascript"}},
+				Author: "Jane Doe",
+			},
+		},
+		{
+			file: "block.ps1",
+			want: ScriptTags{
+				Tags:        []Tag{{Category: "platforms", Value: "windows"}, {Category: "packagemanagers", Value: "choco"}, {Category: "packagemanagers", Value: "winget"}},
+				Description: "Installs packages with Chocolatey",
+			},
+		},
+		{
+			file: "block.ts",
+			want: ScriptTags{
+				Tags:    []Tag{{Category: "platforms", Value: "linux"}, {Category: "languages", Value: "typescript"}},
+				Version: "1.2.0",
+			},
+		},
+		{
+			file: "begin-end.rb",
+			want: ScriptTags{
+				Tags: []Tag{{Category: "platforms", Value: "linux"}, {Category: "platforms", Value: "macos"}, {Category: "languages", Value: "ruby"}},
+			},
+		},
+		{
+			file: "front-matter.sh",
+			want: ScriptTags{
+				Tags: []Tag{
+					{Category: "distros", Value: "ubuntu"},
+					{Category: "platforms", Value: "linux"},
+					{Category: "platforms", Value: "macos"},
+					{Category: "distros", Value: "debian-family", Implied: true},
+				},
+				Description: "Installs developer tools",
+				Requires:    []string{"git", "curl"},
+			},
+		},
+		{
+			file:     "long-line.sh",
+			longLine: true,
+			want: ScriptTags{
+				Tags: []Tag{{Category: "platforms", Value: "linux"}, {Category: "categories", Value: "setup"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join("testdata", "headers", tt.file)
+			if tt.longLine {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				path = filepath.Join(t.TempDir(), tt.file)
+				data = append(data, "DATA="+strings.Repeat("x", 100*1024)+"\n"...)
+				if err := os.WriteFile(path, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := ParseTags(path)
+			if err != nil {
+				t.Fatalf("ParseTags(%s): %v", tt.file, err)
+			}
+			want := tt.want
+			want.Path = path
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("ParseTags(%s) =\n%+v\nwant\n%+v", tt.file, *got, want)
+			}
+		})
+	}
+}
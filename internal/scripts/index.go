@@ -0,0 +1,289 @@
+package scripts
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// indexVersion changes whenever header parsing changes, so indexes written
+// by older versions are rebuilt instead of trusted.
+const indexVersion = 3
+
+// maxIndexWorkers bounds how many scripts are parsed at once.
+const maxIndexWorkers = 8
+
+// Index is a persistent catalog of parsed script headers. Entries are keyed
+// by path and reused while the file's modification time and size stay the
+// same, so listings don't re-open every script.
+type Index struct {
+	mu      sync.Mutex
+	file    string
+	rules   string // Fingerprint of the tag rules the entries were parsed with
+	entries map[string]indexEntry
+	dirty   bool // Entries changed since the index was loaded or saved
+}
+
+// indexEntry is the indexed header of one script.
+type indexEntry struct {
+	ModTime int64       `json:"mtime"`
+	Size    int64       `json:"size"`
+	Tags    *ScriptTags `json:"tags,omitempty"`
+}
+
+// indexFile is the on-disk form of an Index.
+type indexFile struct {
+	Version int                   `json:"version"`
+	Rules   string                `json:"rules"`
+	Entries map[string]indexEntry `json:"entries"`
+}
+
+// activeIndex is the index listings read headers through, if any.
+var activeIndex *Index
+
+// UseIndex makes GetItems, GetAllScriptsRecursively and LoadItem read script
+// headers through idx. Passing nil parses every script again.
+func UseIndex(idx *Index) {
+	activeIndex = idx
+}
+
+// RefreshIndex refreshes the index in use, if any, for the scripts below
+// root.
+func RefreshIndex(root string) error {
+	if activeIndex == nil {
+		return nil
+	}
+	return activeIndex.Refresh(root)
+}
+
+// ForgetIndexed drops path from the index in use, if any.
+func ForgetIndexed(path string) {
+	if activeIndex != nil {
+		activeIndex.Forget(path)
+	}
+}
+
+// IndexPath returns where the index is stored under the cache directory.
+func IndexPath(cacheDir string) string {
+	return filepath.Join(cacheDir, "index.json")
+}
+
+// OpenIndex loads the index stored in file. A missing, unreadable or
+// outdated index starts out empty.
+func OpenIndex(file string) *Index {
+	idx := &Index{file: file, entries: make(map[string]indexEntry)}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return idx
+	}
+	var stored indexFile
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != indexVersion {
+		return idx
+	}
+	if stored.Entries != nil {
+		idx.rules = stored.Rules
+		idx.entries = stored.Entries
+	}
+	return idx
+}
+
+// Len returns the number of indexed scripts.
+func (idx *Index) Len() int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return len(idx.entries)
+}
+
+// Save writes the index to disk if it changed.
+func (idx *Index) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(indexFile{Version: indexVersion, Rules: idx.rules, Entries: idx.entries})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(idx.file), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves half an index
+	tmp := idx.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, idx.file); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+// Tags returns the parsed header of the script at path, parsing it only if
+// info shows it changed since it was indexed. The result is a copy the
+// caller may modify.
+func (idx *Index) Tags(path string, info fs.FileInfo) *ScriptTags {
+	idx.mu.Lock()
+	idx.checkRules()
+	entry, ok := idx.entries[path]
+	idx.mu.Unlock()
+
+	if !ok || !entry.matches(info) {
+		entry = newIndexEntry(path, info)
+		idx.mu.Lock()
+		idx.entries[path] = entry
+		idx.dirty = true
+		idx.mu.Unlock()
+	}
+	return entry.Tags.clone()
+}
+
+// Forget drops the entries of path and of anything below it, so they are
+// parsed again when next listed.
+func (idx *Index) Forget(path string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	prefix := path + string(filepath.Separator)
+	for indexed := range idx.entries {
+		if indexed == path || strings.HasPrefix(indexed, prefix) {
+			delete(idx.entries, indexed)
+			idx.dirty = true
+		}
+	}
+}
+
+// Refresh brings the index up to date with the scripts below root: changed
+// files are parsed across a bounded pool of workers and entries of deleted
+// files are dropped. The index is saved if anything changed.
+func (idx *Index) Refresh(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	type job struct {
+		path string
+		info fs.FileInfo
+	}
+	var found []job
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries, as listings do
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !IsScriptPath(path) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			found = append(found, job{path: path, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var stale []job
+	seen := make(map[string]bool, len(found))
+	idx.mu.Lock()
+	idx.checkRules()
+	for _, j := range found {
+		seen[j.path] = true
+		if entry, ok := idx.entries[j.path]; !ok || !entry.matches(j.info) {
+			stale = append(stale, j)
+		}
+	}
+	for path := range idx.entries {
+		if !seen[path] && strings.HasPrefix(path, root+string(filepath.Separator)) {
+			delete(idx.entries, path)
+			idx.dirty = true
+		}
+	}
+	idx.mu.Unlock()
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < min(runtime.NumCPU(), maxIndexWorkers, max(len(stale), 1)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				entry := newIndexEntry(j.path, j.info)
+				idx.mu.Lock()
+				idx.entries[j.path] = entry
+				idx.dirty = true
+				idx.mu.Unlock()
+			}
+		}()
+	}
+	for _, j := range stale {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+
+	return idx.Save()
+}
+
+// checkRules empties the index if the tag rules changed since its entries
+// were parsed. idx.mu must be held.
+func (idx *Index) checkRules() {
+	if rules := tagRulesFingerprint(); idx.rules != rules {
+		if len(idx.entries) > 0 {
+			idx.entries = make(map[string]indexEntry)
+		}
+		idx.rules = rules
+		idx.dirty = true
+	}
+}
+
+// newIndexEntry parses a script for the index.
+func newIndexEntry(path string, info fs.FileInfo) indexEntry {
+	tags, _ := ParseTags(path) // Ignore errors, just index nil
+	return indexEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Tags: tags}
+}
+
+// matches reports whether the entry still describes the file.
+func (e indexEntry) matches(info fs.FileInfo) bool {
+	return e.ModTime == info.ModTime().UnixNano() && e.Size == info.Size()
+}
+
+// clone returns a copy of the tags whose tag list can be appended to
+// without changing the original.
+func (st *ScriptTags) clone() *ScriptTags {
+	if st == nil {
+		return nil
+	}
+	copied := *st
+	copied.Tags = append([]Tag(nil), st.Tags...)
+	return &copied
+}
+
+// loadTags parses the header of a script, through the active index when
+// there is one. info may be nil if the file has not been stat'ed yet.
+func loadTags(path string, info fs.FileInfo) *ScriptTags {
+	if activeIndex != nil {
+		if info == nil {
+			info, _ = os.Stat(path)
+		}
+		if info != nil {
+			return activeIndex.Tags(path, info)
+		}
+	}
+	tags, _ := ParseTags(path) // Ignore errors, just use nil
+	return tags
+}
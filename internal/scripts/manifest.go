@@ -0,0 +1,128 @@
+package scripts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFiles are the per-directory metadata files go-pwr reads, in order
+// of precedence. Only the first one found in a directory is used.
+var ManifestFiles = []string{"scriptbin.yaml", "scriptbin.yml", ".go-pwr.json"}
+
+// Manifest describes the scripts of one directory for files that cannot
+// carry a #*Tags: header, or to override what the header says. The
+// manifest at the repository root may also set tag rules and workflows.
+type Manifest struct {
+	Path      string                `yaml:"-" json:"-"`
+	Scripts   map[string]ScriptMeta `yaml:"scripts" json:"scripts"`     // Keyed by file or directory name
+	Workflows map[string]Workflow   `yaml:"workflows" json:"workflows"` // Keyed by name; only read at the root
+
+	TagRules `yaml:",inline"` // synonyms and parents; only read at the root
+}
+
+// ScriptMeta is the manifest entry for a single script.
+type ScriptMeta struct {
+	Name        string              `yaml:"name" json:"name"`               // Display name
+	Description string              `yaml:"description" json:"description"` // Shown and searched in the list
+	Aliases     []string            `yaml:"aliases" json:"aliases"`         // Extra search terms
+	Tags        map[string][]string `yaml:"tags" json:"tags"`               // Category to values, like #*Tags:
+	Order       int                 `yaml:"order" json:"order"`             // Lower first; 0 keeps alphabetical order
+	Hidden      bool                `yaml:"hidden" json:"hidden"`           // Leave the entry out of listings
+	Args        []string            `yaml:"args" json:"args"`               // Default arguments when run
+}
+
+// LoadManifest reads the manifest of dir. It returns nil without an error
+// if the directory has none.
+func LoadManifest(dir string) (*Manifest, error) {
+	for _, name := range ManifestFiles {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		manifest := &Manifest{Path: path}
+		if strings.HasSuffix(name, ".json") {
+			err = json.Unmarshal(data, manifest)
+		} else {
+			err = yaml.Unmarshal(data, manifest)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		return manifest, nil
+	}
+	return nil, nil
+}
+
+// Entry returns the manifest entry for a file or directory name.
+func (m *Manifest) Entry(name string) (ScriptMeta, bool) {
+	if m == nil {
+		return ScriptMeta{}, false
+	}
+	meta, ok := m.Scripts[strings.TrimSuffix(name, "/")]
+	return meta, ok
+}
+
+// IsHidden reports whether the manifest hides the named entry.
+func (m *Manifest) IsHidden(name string) bool {
+	meta, ok := m.Entry(name)
+	return ok && meta.Hidden
+}
+
+// loadManifestQuietly loads a manifest for listing, where a broken manifest
+// should not hide the scripts next to it. go-pwr lint reports the error.
+func loadManifestQuietly(dir string) *Manifest {
+	manifest, _ := LoadManifest(dir)
+	return manifest
+}
+
+// applyManifest merges the manifest entry for file into a script item.
+// displayDir is prefixed to a custom name in recursive listings.
+func applyManifest(item Item, manifest *Manifest, file, displayDir string) Item {
+	meta, ok := manifest.Entry(file)
+	if !ok {
+		return item
+	}
+
+	if meta.Name != "" {
+		item.name = meta.Name
+		if displayDir != "" {
+			item.name = filepath.Join(displayDir, meta.Name)
+		}
+	}
+	item.summary = meta.Description
+	item.aliases = meta.Aliases
+	item.args = meta.Args
+	item.order = meta.Order
+
+	if len(meta.Tags) > 0 {
+		if item.tags == nil {
+			item.tags = &ScriptTags{Path: item.path}
+		}
+		categories := make([]string, 0, len(meta.Tags))
+		for category := range meta.Tags {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			for _, value := range meta.Tags[category] {
+				tag := Tag{Category: strings.ToLower(category), Value: strings.ToLower(value)}
+				if !item.tags.HasTag(tag.Category, tag.Value) {
+					item.tags.Tags = append(item.tags.Tags, tag)
+				}
+			}
+		}
+		item.tags.Tags = normalizeTags(item.tags.Tags)
+	}
+	return item
+}
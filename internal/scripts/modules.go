@@ -0,0 +1,64 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isModuleRoot reports whether dir is the checkout of a git submodule.
+// Submodule checkouts have a .git file pointing into the superproject,
+// whereas the superproject itself has a .git directory.
+func isModuleRoot(dir string) bool {
+	info, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil && !info.IsDir()
+}
+
+// moduleResolver finds the submodule namespace of directories, remembering
+// the answer for each directory it has seen.
+type moduleResolver struct {
+	memo map[string]string
+}
+
+// newModuleResolver creates an empty module resolver.
+func newModuleResolver() *moduleResolver {
+	return &moduleResolver{memo: make(map[string]string)}
+}
+
+// moduleOf returns the namespace of the submodule containing dir, e.g.
+// "team-tools" or "team-tools/vendor" for nested submodules, or an empty
+// string if dir is not inside a submodule.
+func (r *moduleResolver) moduleOf(dir string) string {
+	if module, ok := r.memo[dir]; ok {
+		return module
+	}
+
+	module := ""
+	parent := filepath.Dir(dir)
+	if info, err := os.Lstat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+		// Reached the superproject
+	} else if parent != dir {
+		module = r.moduleOf(parent)
+		if isModuleRoot(dir) {
+			if module != "" {
+				module += "/"
+			}
+			module += filepath.Base(dir)
+		}
+	}
+
+	r.memo[dir] = module
+	return module
+}
+
+// withModuleTags adds a "module" tag for each level of the namespace, so
+// scripts can be found by the submodule they come from.
+func withModuleTags(tags *ScriptTags, module string) *ScriptTags {
+	if module == "" || tags == nil {
+		return tags
+	}
+	for _, name := range strings.Split(module, "/") {
+		tags.Tags = append(tags.Tags, Tag{Category: "module", Value: strings.ToLower(name)})
+	}
+	return tags
+}
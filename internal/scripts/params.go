@@ -0,0 +1,200 @@
+package scripts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Param types.
+const (
+	ParamString = "string"
+	ParamInt    = "int"
+	ParamBool   = "bool"
+	ParamEnum   = "enum"
+)
+
+// Ways a parameter value is passed to the script.
+const (
+	PassEnv  = "env"  // As an environment variable (the default)
+	PassArg  = "arg"  // As a positional argument, in declaration order
+	PassFlag = "flag" // As "--name value"
+)
+
+// Param is a parameter a script declares with a "# Param:" header line.
+type Param struct {
+	Name        string
+	Type        string
+	Options     []string // Allowed values of an enum
+	Required    bool
+	Default     string
+	Description string
+	Env         string // Environment variable name when passed by env
+	Pass        string // env, arg or flag
+}
+
+var (
+	paramNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	paramEnumPattern = regexp.MustCompile(`(?i)^enum\((.*)\)$`)
+)
+
+// ParseParam parses the text after "# Param:", for example
+// `hostname string required "Target host"` or `env enum(dev,prod) default=dev`.
+// After the name and type, the modifiers required, default=<value>,
+// env=<VAR>, arg and flag may follow in any order, plus a quoted description.
+func ParseParam(spec string) (Param, error) {
+	fields, err := splitParamSpec(spec)
+	if err != nil {
+		return Param{}, err
+	}
+	if len(fields) < 2 {
+		return Param{}, fmt.Errorf("expected \"<name> <type> [modifiers] [\\\"description\\\"]\"")
+	}
+
+	param := Param{Name: fields[0], Pass: PassEnv}
+	if !paramNamePattern.MatchString(param.Name) {
+		return Param{}, fmt.Errorf("invalid parameter name %q", param.Name)
+	}
+	param.Env = strings.ToUpper(strings.ReplaceAll(param.Name, "-", "_"))
+
+	kind := strings.ToLower(fields[1])
+	if matches := paramEnumPattern.FindStringSubmatch(fields[1]); matches != nil {
+		param.Type = ParamEnum
+		for _, option := range strings.Split(matches[1], ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				param.Options = append(param.Options, option)
+			}
+		}
+		if len(param.Options) == 0 {
+			return Param{}, fmt.Errorf("enum parameter %q has no options", param.Name)
+		}
+	} else {
+		switch kind {
+		case ParamString, ParamInt, ParamBool:
+			param.Type = kind
+		default:
+			return Param{}, fmt.Errorf("unknown parameter type %q (use string, int, bool or enum(a,b))", fields[1])
+		}
+	}
+
+	for _, field := range fields[2:] {
+		switch {
+		case strings.HasPrefix(field, "\""):
+			param.Description = strings.Trim(field, "\"")
+		case field == "required":
+			param.Required = true
+		case field == PassArg || field == PassFlag:
+			param.Pass = field
+		case strings.HasPrefix(field, "default="):
+			param.Default = strings.Trim(strings.TrimPrefix(field, "default="), "\"")
+		case strings.HasPrefix(field, "env="):
+			param.Env = strings.TrimPrefix(field, "env=")
+			param.Pass = PassEnv
+		default:
+			return Param{}, fmt.Errorf("unknown modifier %q for parameter %q", field, param.Name)
+		}
+	}
+
+	if param.Default != "" {
+		normalized, err := param.Validate(param.Default)
+		if err != nil {
+			return Param{}, fmt.Errorf("invalid default for %q: %v", param.Name, err)
+		}
+		param.Default = normalized
+	}
+	return param, nil
+}
+
+// splitParamSpec splits a parameter declaration on whitespace, keeping
+// double-quoted text together.
+func splitParamSpec(spec string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range strings.TrimSpace(spec) {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+// Validate checks a value against the parameter and returns it normalized.
+// An empty value is only an error for required parameters.
+func (p Param) Validate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if p.Required {
+			return "", fmt.Errorf("%s is required", p.Name)
+		}
+		return "", nil
+	}
+
+	switch p.Type {
+	case ParamInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("%s must be a whole number", p.Name)
+		}
+	case ParamBool:
+		switch strings.ToLower(value) {
+		case "true", "yes", "y", "1", "on":
+			return "true", nil
+		case "false", "no", "n", "0", "off":
+			return "false", nil
+		}
+		return "", fmt.Errorf("%s must be true or false", p.Name)
+	case ParamEnum:
+		for _, option := range p.Options {
+			if strings.EqualFold(option, value) {
+				return option, nil
+			}
+		}
+		return "", fmt.Errorf("%s must be one of: %s", p.Name, strings.Join(p.Options, ", "))
+	}
+	return value, nil
+}
+
+// ParamValues validates values (keyed by parameter name), fills in
+// defaults and returns the arguments and environment ("KEY=value")
+// entries to run the script with.
+func ParamValues(params []Param, values map[string]string) (args, env []string, err error) {
+	for _, p := range params {
+		value, ok := values[p.Name]
+		if !ok || strings.TrimSpace(value) == "" {
+			value = p.Default
+		}
+		value, err := p.Validate(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value == "" && p.Pass != PassArg {
+			continue // Positional arguments keep their place even when empty
+		}
+
+		switch p.Pass {
+		case PassArg:
+			args = append(args, value)
+		case PassFlag:
+			args = append(args, "--"+p.Name, value)
+		default:
+			env = append(env, p.Env+"="+value)
+		}
+	}
+	return args, env, nil
+}
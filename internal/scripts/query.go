@@ -0,0 +1,402 @@
+package scripts
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// Query is a parsed search query. Its syntax:
+//
+//	linux              a tag value, category or script name containing "linux"
+//	=mac               a tag value or script name that is exactly "mac"
+//	platform:linux     a tag in a category starting with "platform" containing "linux"
+//	platform:=mac      the same, matching the value exactly (also platform=mac)
+//	"dev tools"        a phrase, e.g. from a script's description
+//	-windows           scripts that do not match (also NOT windows)
+//	apt OR dnf         either side matches; terms are otherwise ANDed
+//	(apt OR dnf) -wsl  parentheses group
+//
+// Matching ignores case, and terms are compared with tags after tag
+// synonyms, so "osx" finds scripts tagged macOS. The empty query matches
+// everything.
+type Query struct {
+	root queryNode
+}
+
+// queryNode is a node of the parsed query tree.
+type queryNode interface {
+	match(item Item) bool
+}
+
+type (
+	termNode struct {
+		category string // Category prefix, empty to search everything
+		value    string
+		tagValue string // value after tag synonyms, compared with tags
+		exact    bool
+	}
+	notNode struct{ node queryNode }
+	andNode []queryNode
+	orNode  []queryNode
+)
+
+func (t termNode) match(item Item) bool {
+	if item.tags != nil {
+		for _, tag := range item.tags.Tags {
+			if t.category != "" && !strings.HasPrefix(tag.Category, t.category) {
+				continue
+			}
+			if t.matchTag(tag.Value) {
+				return true
+			}
+			// A bare word also finds scripts by category, as the old search did
+			if t.category == "" && !t.exact && strings.Contains(tag.Category, t.value) {
+				return true
+			}
+		}
+	}
+	if t.category != "" {
+		return false
+	}
+
+	for _, text := range append([]string{item.Title(), item.Summary()}, item.Aliases()...) {
+		if text != "" && t.matchValue(strings.ToLower(text)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTag compares a tag value against the term's canonical form.
+func (t termNode) matchTag(value string) bool {
+	if t.exact {
+		return value == t.tagValue
+	}
+	return strings.Contains(value, t.tagValue)
+}
+
+// matchValue compares a lower-case value against the term.
+func (t termNode) matchValue(value string) bool {
+	if t.exact {
+		return value == t.value
+	}
+	return strings.Contains(value, t.value)
+}
+
+func (n notNode) match(item Item) bool { return !n.node.match(item) }
+
+func (n andNode) match(item Item) bool {
+	for _, node := range n {
+		if !node.match(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n orNode) match(item Item) bool {
+	for _, node := range n {
+		if node.match(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseQuery parses a search query. Errors name the column (counted from
+// 1) where the problem is.
+func ParseQuery(input string) (*Query, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return &Query{}, nil
+	}
+
+	p := &queryParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, fmt.Errorf("unexpected ')' at column %d", tok.pos)
+	}
+	return &Query{root: root}, nil
+}
+
+// IsEmpty reports whether the query matches everything.
+func (q *Query) IsEmpty() bool {
+	return q == nil || q.root == nil
+}
+
+// Matches reports whether a script matches the query.
+func (q *Query) Matches(item Item) bool {
+	return q.IsEmpty() || q.root.match(item)
+}
+
+// FilterItemsByQuery keeps the scripts matching the query, and all
+// directories.
+func FilterItemsByQuery(items []list.Item, query *Query) []list.Item {
+	if query.IsEmpty() {
+		return items
+	}
+
+	var filteredItems []list.Item
+	for _, item := range items {
+		if scriptItem, ok := item.(Item); ok {
+			if scriptItem.IsDirectory() || query.Matches(scriptItem) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+	}
+	return filteredItems
+}
+
+// Token kinds of the query language.
+const (
+	tokenTerm = iota
+	tokenOpen
+	tokenClose
+	tokenNot
+	tokenAnd
+	tokenOr
+)
+
+// queryToken is a lexed piece of a query.
+type queryToken struct {
+	kind int
+	term termNode
+	pos  int // Column, counted from 1
+}
+
+// lexQuery splits a query into tokens.
+func lexQuery(input string) ([]queryToken, error) {
+	runes := []rune(input)
+	var tokens []queryToken
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenOpen, pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenClose, pos: pos})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, queryToken{kind: tokenNot, pos: pos})
+			i++
+		default:
+			tok, next, err := lexTerm(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		}
+	}
+	return tokens, nil
+}
+
+// lexTerm reads a term, or an AND/OR/NOT keyword, starting at runes[i].
+func lexTerm(runes []rune, i int) (queryToken, int, error) {
+	tok := queryToken{kind: tokenTerm, pos: i + 1}
+
+	if runes[i] == '=' {
+		tok.term.exact = true
+		i++
+	}
+	word, quoted, i, err := lexWord(runes, i, !tok.term.exact)
+	if err != nil {
+		return tok, i, err
+	}
+
+	if !quoted && !tok.term.exact {
+		switch word {
+		case "AND":
+			return queryToken{kind: tokenAnd, pos: tok.pos}, i, nil
+		case "OR":
+			return queryToken{kind: tokenOr, pos: tok.pos}, i, nil
+		case "NOT":
+			return queryToken{kind: tokenNot, pos: tok.pos}, i, nil
+		}
+	}
+
+	// category:value, category:=value or category=value
+	if !quoted && !tok.term.exact && i < len(runes) && (runes[i] == ':' || runes[i] == '=') {
+		if word == "" {
+			return tok, i, fmt.Errorf("missing category before '%c' at column %d", runes[i], i+1)
+		}
+		operator := runes[i]
+		tok.term.category = strings.ToLower(word)
+		tok.term.exact = operator == '='
+		i++
+		if operator == ':' && i < len(runes) && runes[i] == '=' {
+			tok.term.exact = true
+			i++
+		}
+		word, _, i, err = lexWord(runes, i, false)
+		if err != nil {
+			return tok, i, err
+		}
+		if word == "" {
+			return tok, i, fmt.Errorf("missing value after %q at column %d", tok.term.category+string(operator), tok.pos)
+		}
+	} else if word == "" {
+		if quoted {
+			return tok, i, fmt.Errorf("empty phrase at column %d", tok.pos)
+		}
+		if tok.term.exact {
+			return tok, i, fmt.Errorf("missing value after '=' at column %d", tok.pos)
+		}
+		return tok, i, fmt.Errorf("unexpected %q at column %d", string(runes[i]), i+1)
+	}
+
+	tok.term.value = strings.ToLower(word)
+	tok.term.tagValue = CanonicalTag(tok.term.value)
+	return tok, i, nil
+}
+
+// lexWord reads a quoted phrase or a bare word starting at runes[i]. A bare
+// word ends at whitespace or a parenthesis, and also at ':' or '=' when
+// stopAtOperator is set so a category can be split off.
+func lexWord(runes []rune, i int, stopAtOperator bool) (string, bool, int, error) {
+	if i < len(runes) && runes[i] == '"' {
+		start := i
+		end := i + 1
+		for end < len(runes) && runes[end] != '"' {
+			end++
+		}
+		if end == len(runes) {
+			return "", true, end, fmt.Errorf("missing closing quote for the phrase at column %d", start+1)
+		}
+		return string(runes[start+1 : end]), true, end + 1, nil
+	}
+
+	start := i
+	for i < len(runes) {
+		r := runes[i]
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' || (stopAtOperator && (r == ':' || r == '=')) {
+			break
+		}
+		i++
+	}
+	return string(runes[start:i]), false, i, nil
+}
+
+// queryParser is a recursive descent parser over lexed tokens:
+//
+//	or    = and { "OR" and }
+//	and   = unary { ["AND"] unary }
+//	unary = ("-" | "NOT") unary | "(" or ")" | term
+type queryParser struct {
+	tokens []queryToken
+	next   int
+}
+
+// peek returns the next token, or nil at the end of the query.
+func (p *queryParser) peek() *queryToken {
+	if p.next < len(p.tokens) {
+		return &p.tokens[p.next]
+	}
+	return nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := orNode{left}
+	for tok := p.peek(); tok != nil && tok.kind == tokenOr; tok = p.peek() {
+		p.next++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	var nodes andNode
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind == tokenClose || tok.kind == tokenOr {
+			break
+		}
+		if tok.kind == tokenAnd {
+			if len(nodes) == 0 {
+				return nil, fmt.Errorf("AND at column %d needs a term on both sides", tok.pos)
+			}
+			p.next++
+			if next := p.peek(); next == nil || next.kind == tokenClose || next.kind == tokenOr || next.kind == tokenAnd {
+				return nil, fmt.Errorf("AND at column %d needs a term on both sides", tok.pos)
+			}
+			continue
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		tok := p.peek()
+		switch {
+		case tok != nil && tok.kind == tokenOr:
+			return nil, fmt.Errorf("OR at column %d needs a term on both sides", tok.pos)
+		case tok != nil && tok.kind == tokenClose && p.next > 0 && p.tokens[p.next-1].kind == tokenOpen:
+			return nil, fmt.Errorf("empty parentheses at column %d", p.tokens[p.next-1].pos)
+		case tok != nil && tok.kind == tokenClose:
+			return nil, fmt.Errorf("unexpected ')' at column %d", tok.pos)
+		case p.next > 0 && p.tokens[p.next-1].kind == tokenOr:
+			return nil, fmt.Errorf("OR at column %d needs a term on both sides", p.tokens[p.next-1].pos)
+		}
+		return nil, fmt.Errorf("missing search term at the end of the query")
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	tok := p.peek()
+	p.next++
+
+	switch tok.kind {
+	case tokenNot:
+		if next := p.peek(); next == nil || next.kind == tokenClose || next.kind == tokenOr || next.kind == tokenAnd {
+			return nil, fmt.Errorf("nothing to exclude after the NOT at column %d", tok.pos)
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	case tokenOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next == nil || next.kind != tokenClose {
+			return nil, fmt.Errorf("missing ')' for the '(' at column %d", tok.pos)
+		}
+		p.next++
+		return node, nil
+	}
+	return tok.term, nil
+}
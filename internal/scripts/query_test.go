@@ -0,0 +1,164 @@
+package scripts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// queryItems are the scripts the query tests search.
+var queryItems = []Item{
+	testItem("setup-ubuntu.sh", "Install dev tools", "platforms:linux", "distros:ubuntu", "categories:development"),
+	testItem("brew.sh", "Homebrew bundle for the Mac", "platforms:macos", "packagemanagers:brew"),
+	testItem("choco.ps1", "Chocolatey packages", "platforms:windows", "packagemanagers:choco", "languages:powershell"),
+	testItem("apt-wsl.sh", "Update apt inside WSL", "platforms:linux", "platforms:wsl", "packagemanagers:apt"),
+	testItem("fedora.sh", "Fedora workstation", "platforms:linux", "distros:fedora", "packagemanagers:dnf"),
+	testItem("mac", "Named like a tag", "categories:misc"),
+}
+
+// testItem builds a script item with a description and category:value tags.
+func testItem(name, description string, tags ...string) Item {
+	st := &ScriptTags{Path: "/scripts/" + name, Description: description}
+	for _, tag := range tags {
+		category, value, _ := strings.Cut(tag, ":")
+		st.Tags = append(st.Tags, Tag{Category: category, Value: value})
+	}
+	return Item{name: name, path: st.Path, tags: st}
+}
+
+// matchingNames returns the names of the query items the query matches.
+func matchingNames(t *testing.T, input string) []string {
+	t.Helper()
+	query, err := ParseQuery(input)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q): %v", input, err)
+	}
+	var names []string
+	for _, item := range queryItems {
+		if query.Matches(item) {
+			names = append(names, item.Title())
+		}
+	}
+	return names
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`"dev tools`, "missing closing quote for the phrase at column 1"},
+		{`linux "dev`, "missing closing quote for the phrase at column 7"},
+		{`""`, "empty phrase at column 1"},
+		{`linux ()`, "empty parentheses at column 7"},
+		{`(linux`, "missing ')' for the '(' at column 1"},
+		{`linux OR`, "OR at column 7 needs a term on both sides"},
+		{`OR linux`, "OR at column 1 needs a term on both sides"},
+		{`linux OR OR mac`, "OR at column 10 needs a term on both sides"},
+		{`linux AND`, "AND at column 7 needs a term on both sides"},
+		{`AND linux`, "AND at column 1 needs a term on both sides"},
+		{`linux NOT`, "nothing to exclude after the NOT at column 7"},
+		{`NOT OR linux`, "nothing to exclude after the NOT at column 1"},
+		{`linux)`, "unexpected ')' at column 6"},
+		{`) linux`, "unexpected ')' at column 1"},
+		{`platform:`, `missing value after "platform:" at column 1`},
+		{`linux platform=`, `missing value after "platform=" at column 7`},
+		{`=`, "missing value after '=' at column 1"},
+		{`:linux`, "missing category before ':' at column 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			if err == nil {
+				t.Fatalf("ParseQuery(%q) succeeded, want error %q", tt.query, tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("ParseQuery(%q) error = %q, want %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"empty matches everything", "", []string{"setup-ubuntu.sh", "brew.sh", "choco.ps1", "apt-wsl.sh", "fedora.sh", "mac"}},
+		{"bare word searches tags", "linux", []string{"setup-ubuntu.sh", "apt-wsl.sh", "fedora.sh"}},
+		{"bare word searches categories", "packagemanagers", []string{"brew.sh", "choco.ps1", "apt-wsl.sh", "fedora.sh"}},
+		{"bare word searches names", "choco", []string{"choco.ps1"}},
+		{"ignores case", "LINUX", []string{"setup-ubuntu.sh", "apt-wsl.sh", "fedora.sh"}},
+
+		// Exact and substring matching
+		{"substring", "linu", []string{"setup-ubuntu.sh", "apt-wsl.sh", "fedora.sh"}},
+		{"exact needs the whole value", "=linu", nil},
+		{"exact tag", "=linux", []string{"setup-ubuntu.sh", "apt-wsl.sh", "fedora.sh"}},
+		{"exact name", "=brew.sh", []string{"brew.sh"}},
+		{"exact skips categories", "=platforms", nil},
+
+		// Categories
+		{"category substring", "platforms:lin", []string{"setup-ubuntu.sh", "apt-wsl.sh", "fedora.sh"}},
+		{"category prefix", "platform:windows", []string{"choco.ps1"}},
+		{"category exact with := needs the whole value", "platforms:=lin", nil},
+		{"category exact with :=", "platforms:=wsl", []string{"apt-wsl.sh"}},
+		{"category exact with =", "platforms=wsl", []string{"apt-wsl.sh"}},
+		{"category skips other categories", "distros:linux", nil},
+		{"category skips names", "platforms:choco", nil},
+
+		// Phrases
+		{"phrase in summary", `"dev tools"`, []string{"setup-ubuntu.sh"}},
+		{"phrase is one term", `"tools dev"`, nil},
+		{"phrase in category", `categories:"development"`, []string{"setup-ubuntu.sh"}},
+
+		// Synonyms
+		{"synonym", "osx", []string{"brew.sh"}},
+		{"synonym or name", "mac", []string{"brew.sh", "mac"}},
+		{"exact synonym or name", "=mac", []string{"brew.sh", "mac"}},
+		{"synonym in category", "platform:=darwin", []string{"brew.sh"}},
+		{"synonym exact", "=win", []string{"choco.ps1"}},
+
+		// Operators and precedence
+		{"and by default", "linux apt", []string{"apt-wsl.sh"}},
+		{"explicit and", "linux AND apt", []string{"apt-wsl.sh"}},
+		{"minus", "linux -wsl", []string{"setup-ubuntu.sh", "fedora.sh"}},
+		{"not", "linux NOT wsl", []string{"setup-ubuntu.sh", "fedora.sh"}},
+		{"minus binds tighter than and", "-wsl linux", []string{"setup-ubuntu.sh", "fedora.sh"}},
+		{"double negation", "linux NOT -wsl", []string{"apt-wsl.sh"}},
+		{"or", "brew OR choco", []string{"brew.sh", "choco.ps1"}},
+		{"and binds tighter than or", "ubuntu OR linux dnf", []string{"setup-ubuntu.sh", "fedora.sh"}},
+		{"and binds tighter than or on the left", "linux dnf OR brew", []string{"brew.sh", "fedora.sh"}},
+		{"parentheses group or", "(apt OR dnf) -wsl", []string{"fedora.sh"}},
+		{"parentheses change precedence", "linux (ubuntu OR dnf)", []string{"setup-ubuntu.sh", "fedora.sh"}},
+		{"negated group", "linux -(ubuntu OR fedora)", []string{"apt-wsl.sh"}},
+		{"nested groups", "((brew OR choco) OR (dnf))", []string{"brew.sh", "choco.ps1", "fedora.sh"}},
+		{"hyphen inside a word", "apt-wsl", []string{"apt-wsl.sh"}},
+		{"lower-case and is a word", "linux and", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchingNames(t, tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q matched %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterItemsByQueryKeepsDirectories(t *testing.T) {
+	query, err := ParseQuery("=nothing-is-called-this")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []list.Item{Item{name: "tools/", path: "/scripts/tools"}}
+	for _, item := range queryItems {
+		items = append(items, item)
+	}
+	items = FilterItemsByQuery(items, query)
+	if len(items) != 1 || items[0].(Item).Title() != "tools/" {
+		t.Errorf("FilterItemsByQuery kept %v, want only the directory", items)
+	}
+}
@@ -2,21 +2,36 @@
 package scripts
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
-	"sync"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
 )
 
 // Item represents a script or directory item.
 type Item struct {
-	name string
-	path string
-	tags *ScriptTags
+	name     string
+	path     string
+	tags     *ScriptTags
+	module   string // Submodule namespace the item belongs to
+	isModule bool   // Directory that is the root of a submodule
+
+	// Metadata from the directory manifest
+	summary string
+	aliases []string
+	args    []string
+	order   int
+
+	incompatible string           // Why the script cannot run on this host, if it cannot
+	unmet        []platform.Unmet // Requirements this host does not meet
+
+	matches      []ContentMatch // Lines found by a content search
+	matchedRunes []int          // Title characters matched by a fuzzy search
 }
 
 // Title returns the display name of the item.
@@ -28,6 +43,12 @@ func (s Item) Description() string { return s.path }
 // FilterValue returns the value used for filtering (includes tags).
 func (s Item) FilterValue() string { 
 	filterValue := s.name
+	for _, alias := range s.aliases {
+		filterValue += " " + alias
+	}
+	if summary := s.Summary(); summary != "" {
+		filterValue += " " + summary
+	}
 	if s.tags != nil {
 		// Add tag values to filter string for better search
 		for _, tag := range s.tags.Tags {
@@ -42,50 +63,113 @@ func (s Item) GetTags() *ScriptTags {
 	return s.tags
 }
 
-// IsDirectory returns true if the item is a directory.
-func (s Item) IsDirectory() bool {
-	return strings.HasSuffix(s.name, "/")
+// Summary returns the description of the item, preferring the manifest
+// over the script header.
+func (s Item) Summary() string {
+	if s.summary == "" && s.tags != nil {
+		return s.tags.Description
+	}
+	return s.summary
 }
 
-// IsScript returns true if the item is a supported script file.
-func (s Item) IsScript() bool {
-	ext := strings.ToLower(filepath.Ext(s.name))
-	return ext == ".sh" || ext == ".ps1" || ext == ".bat" || ext == ".cmd"
+// Aliases returns the alternative names of the item.
+func (s Item) Aliases() []string {
+	return s.aliases
+}
+
+// Args returns the default arguments the script is run with.
+func (s Item) Args() []string {
+	return s.args
 }
 
-// Cache provides thread-safe caching for script contents.
-type Cache struct {
-	mu    sync.RWMutex
-	cache map[string]string
+// IsCompatible reports whether the script's tags allow this host.
+func (s Item) IsCompatible() bool {
+	return s.incompatible == ""
 }
 
-// NewCache creates a new script cache.
-func NewCache() *Cache {
-	return &Cache{
-		cache: make(map[string]string),
+// Incompatibility returns why the script cannot run on this host.
+func (s Item) Incompatibility() string {
+	return s.incompatible
+}
+
+// Requires returns the commands and files the script declares it needs.
+func (s Item) Requires() []string {
+	if s.tags == nil {
+		return nil
 	}
+	return s.tags.Requires
+}
+
+// UnmetRequirements returns the requirements this host did not meet when
+// the script was listed. Minimum versions count only once probed.
+func (s Item) UnmetRequirements() []platform.Unmet {
+	return s.unmet
+}
+
+// Privilege returns the privilege level the script demands, if any.
+func (s Item) Privilege() string {
+	if s.tags == nil {
+		return ""
+	}
+	return s.tags.Privilege()
+}
+
+// NeedsAdmin reports whether the script must run with administrator rights.
+func (s Item) NeedsAdmin() bool {
+	return s.Privilege() == PrivilegeAdmin
+}
+
+// Params returns the parameters the script declares.
+func (s Item) Params() []Param {
+	if s.tags == nil {
+		return nil
+	}
+	return s.tags.Params
+}
+
+// Module returns the submodule namespace of the item, if any.
+func (s Item) Module() string {
+	return s.module
+}
+
+// IsModule returns true if the item is the root directory of a submodule.
+func (s Item) IsModule() bool {
+	return s.isModule
+}
+
+// IsDirectory returns true if the item is a directory.
+func (s Item) IsDirectory() bool {
+	return strings.HasSuffix(s.name, "/")
 }
 
-// Get retrieves content from cache.
-func (sc *Cache) Get(path string) (string, bool) {
-	sc.mu.RLock()
-	defer sc.mu.RUnlock()
-	content, exists := sc.cache[path]
-	return content, exists
+// IsScript returns true if the item is a supported script file.
+// Only directories and scripts are ever listed.
+func (s Item) IsScript() bool {
+	return !s.IsDirectory()
 }
 
-// Set stores content in cache.
-func (sc *Cache) Set(path, content string) {
-	sc.mu.Lock()
-	defer sc.mu.Unlock()
-	sc.cache[path] = content
+// IsScriptPath returns true if go-pwr can run the file, either through its
+// extension or through a shebang line.
+func IsScriptPath(path string) bool {
+	return platform.IsScriptPath(path)
 }
 
-// Clear empties the cache.
-func (sc *Cache) Clear() {
-	sc.mu.Lock()
-	defer sc.mu.Unlock()
-	sc.cache = make(map[string]string)
+// LoadItem builds the script item for a single file, with its header tags
+// and manifest metadata.
+func LoadItem(path string) (Item, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return Item{}, err
+	}
+	if !IsScriptPath(path) {
+		return Item{}, fmt.Errorf("%s is not a supported script", path)
+	}
+
+	dir, name := filepath.Split(path)
+	module := newModuleResolver().moduleOf(filepath.Clean(dir))
+	tags := loadTags(path, nil)
+	item := Item{name: name, path: path, tags: withModuleTags(tags, module), module: module}
+	return withCompatibility(applyManifest(item, loadManifestQuietly(dir), name, "")), nil
 }
 
 // GetItems returns all script items in the given directory.
@@ -98,6 +182,8 @@ func GetItems(root string) []list.Item {
 
 	// Pre-allocate slice with estimated capacity
 	items = make([]list.Item, 0, len(entries))
+	module := newModuleResolver().moduleOf(root)
+	manifest := loadManifestQuietly(root)
 
 	// Sort entries: directories first, then files, both alphabetically
 	sort.Slice(entries, func(i, j int) bool {
@@ -113,29 +199,47 @@ func GetItems(root string) []list.Item {
 	for _, entry := range entries {
 		name := entry.Name()
 		// Skip hidden files and directories
-		if strings.HasPrefix(name, ".") {
+		if strings.HasPrefix(name, ".") || manifest.IsHidden(name) {
 			continue
 		}
 
 		path := filepath.Join(root, name)
 		if entry.IsDir() {
-			items = append(items, Item{name: name + "/", path: path, tags: nil})
+			items = append(items, Item{name: name + "/", path: path, tags: nil, module: module, isModule: isModuleRoot(path)})
 		} else {
 			// Only include supported script files
-			ext := strings.ToLower(filepath.Ext(name))
-			if ext == ".sh" || ext == ".ps1" || ext == ".bat" || ext == ".cmd" {
+			if IsScriptPath(path) {
 				// Parse tags for script files
-				tags, _ := ParseTags(path) // Ignore errors, just use nil
-				items = append(items, Item{name: name, path: path, tags: tags})
+				info, _ := entry.Info()
+				tags := loadTags(path, info)
+				item := Item{name: name, path: path, tags: withModuleTags(tags, module), module: module}
+				items = append(items, withCompatibility(applyManifest(item, manifest, name, "")))
 			}
 		}
 	}
+	sortByManifestOrder(items)
 	return items
 }
 
+// sortByManifestOrder moves entries with a manifest order ahead of the rest,
+// keeping directories before files.
+func sortByManifestOrder(items []list.Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].(Item), items[j].(Item)
+		if a.IsDirectory() != b.IsDirectory() {
+			return a.IsDirectory()
+		}
+		if a.order == 0 || b.order == 0 {
+			return a.order != 0 && b.order == 0
+		}
+		return a.order < b.order
+	})
+}
+
 // GetAllScriptsRecursively returns all script items recursively from the given directory
 func GetAllScriptsRecursively(root string) []list.Item {
 	var allItems []list.Item
+	modules := newModuleResolver()
 	
 	// Use a helper function to recursively walk directories
 	var walkDir func(string, string)
@@ -144,11 +248,13 @@ func GetAllScriptsRecursively(root string) []list.Item {
 		if err != nil {
 			return
 		}
+		module := modules.moduleOf(currentPath)
+		manifest := loadManifestQuietly(currentPath)
 		
 		for _, entry := range entries {
 			name := entry.Name()
 			// Skip hidden files and directories
-			if strings.HasPrefix(name, ".") {
+			if strings.HasPrefix(name, ".") || manifest.IsHidden(name) {
 				continue
 			}
 			
@@ -163,15 +269,17 @@ func GetAllScriptsRecursively(root string) []list.Item {
 				walkDir(fullPath, displayPath)
 			} else {
 				// Only include supported script files
-				ext := strings.ToLower(filepath.Ext(name))
-				if ext == ".sh" || ext == ".ps1" || ext == ".bat" || ext == ".cmd" {
+				if IsScriptPath(fullPath) {
 					// Parse tags for script files
-					tags, _ := ParseTags(fullPath) // Ignore errors, just use nil
-					allItems = append(allItems, Item{
-						name: displayPath, // Show relative path from root
-						path: fullPath,    // Keep full path for execution
-						tags: tags,
-					})
+					info, _ := entry.Info()
+					tags := loadTags(fullPath, info)
+					item := Item{
+						name:   displayPath, // Show relative path from root
+						path:   fullPath,    // Keep full path for execution
+						tags:   withModuleTags(tags, module),
+						module: module,
+					}
+					allItems = append(allItems, withCompatibility(applyManifest(item, manifest, name, relativePath)))
 				}
 			}
 		}
@@ -213,19 +321,18 @@ func FilterItemsByTags(items []list.Item, searchTags []string) []list.Item {
 // ReadContent reads the content of a script file, using cache when possible.
 func ReadContent(path string, cache *Cache) string {
 	// Check cache first
-	if content, exists := cache.Get(path); exists {
+	if content, exists := cache.Get(path, "raw"); exists {
 		return content
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		errMsg := "Error reading file: " + err.Error()
-		cache.Set(path, errMsg) // Cache errors too to avoid repeated attempts
-		return errMsg
+		// Errors are not cached, so the next preview tries again
+		return "Error reading file: " + err.Error()
 	}
 
 	content := string(data)
-	cache.Set(path, content)
+	cache.Set(path, "raw", content)
 	return content
 }
 
@@ -263,43 +370,48 @@ func getBatCommand() string {
 
 // ReadContentWithHighlighting reads the content of a script file with syntax highlighting using bat.
 func ReadContentWithHighlighting(path string, cache *Cache) string {
-	// Check cache first
-	if content, exists := cache.Get(path); exists {
-		return content
-	}
-
 	// Use bat for syntax highlighting
 	batCmd := getBatCommand()
 	if batCmd == "" {
+		if content, exists := cache.Get(path, "plain"); exists {
+			return content
+		}
 		// If bat is not available, show a helpful message
 		content := "bat is not installed. Install it for syntax highlighting:\n" +
 			"Windows: winget install sharkdp.bat\n" +
 			"macOS: brew install bat\n" +
 			"Ubuntu: sudo apt install bat\n\n" +
 			"Raw file content:\n" + readRawContent(path)
-		cache.Set(path, content)
+		cache.Set(path, "plain", content)
 		return content
 	}
 
 	// Use bat with DarkNeon theme
-	cmd := exec.Command(batCmd, 
+	args := []string{
 		"--theme=DarkNeon",
 		"--color=always",
 		"--style=numbers",
 		"--paging=never",
-		path,
-	)
+	}
+	if interp, ok := platform.ResolveInterpreter(path); ok && interp.Language != "" {
+		args = append(args, "--language="+interp.Language)
+	}
+
+	// The rendering depends on the bat options, so they are part of the key
+	variant := batCmd + " " + strings.Join(args, " ")
+	if content, exists := cache.Get(path, variant); exists {
+		return content
+	}
+	cmd := exec.Command(batCmd, append(args, path)...)
 	
 	output, err := cmd.Output()
 	if err != nil {
-		// If bat fails, show error and raw content
-		content := "bat failed: " + err.Error() + "\n\nRaw content:\n" + readRawContent(path)
-		cache.Set(path, content)
-		return content
+		// If bat fails, show error and raw content; not cached so it is retried
+		return "bat failed: " + err.Error() + "\n\nRaw content:\n" + readRawContent(path)
 	}
 	
 	content := string(output)
-	cache.Set(path, content)
+	cache.Set(path, variant, content)
 	return content
 }
 
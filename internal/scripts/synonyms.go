@@ -0,0 +1,173 @@
+package scripts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// TagRules normalize tag values so scripts tagged "Mac", "macOS" and "osx"
+// are found the same way.
+type TagRules struct {
+	Synonyms map[string]string   `yaml:"synonyms" json:"synonyms"` // Value to canonical value, e.g. "osx": "macos"
+	Parents  map[string][]string `yaml:"parents" json:"parents"`   // Value to broader values it implies, e.g. "ubuntu": ["debian-family"]
+}
+
+// DefaultTagRules ship with go-pwr. Repositories and users add to them and
+// may override single entries; mapping a value to itself turns a default
+// synonym off.
+var DefaultTagRules = TagRules{
+	Synonyms: map[string]string{
+		// Platforms
+		"mac":    "macos",
+		"osx":    "macos",
+		"darwin": "macos",
+		"win":    "windows",
+		// Languages
+		"pwsh":    "powershell",
+		"ps1":     "powershell",
+		"py":      "python",
+		"python3": "python",
+		"js":      "javascript",
+		"ts":      "typescript",
+		"rb":      "ruby",
+		// Architectures
+		"x86_64":  "amd64",
+		"x64":     "amd64",
+		"aarch64": "arm64",
+		// Package managers, named as the command go-pwr looks for
+		"chocolatey": "choco",
+		"homebrew":   "brew",
+		"apt-get":    "apt",
+	},
+	Parents: map[string][]string{
+		"ubuntu":      {"debian-family"},
+		"debian":      {"debian-family"},
+		"mint":        {"debian-family"},
+		"linuxmint":   {"debian-family"},
+		"pop":         {"debian-family"},
+		"kali":        {"debian-family"},
+		"raspbian":    {"debian-family"},
+		"fedora":      {"redhat-family"},
+		"rhel":        {"redhat-family"},
+		"centos":      {"redhat-family"},
+		"rocky":       {"redhat-family"},
+		"almalinux":   {"redhat-family"},
+		"arch":        {"arch-family"},
+		"manjaro":     {"arch-family"},
+		"endeavouros": {"arch-family"},
+	},
+}
+
+var (
+	rulesMu          sync.RWMutex
+	activeRules      = mergeTagRules(DefaultTagRules)
+	activeRulesPrint = activeRules.fingerprint()
+)
+
+// UseTagRules makes the defaults, overridden by each of layers in turn, the
+// rules tags are normalized with.
+func UseTagRules(layers ...TagRules) {
+	rules := mergeTagRules(append([]TagRules{DefaultTagRules}, layers...)...)
+	rulesMu.Lock()
+	activeRules, activeRulesPrint = rules, rules.fingerprint()
+	rulesMu.Unlock()
+}
+
+// LoadTagRules uses the default rules, then those of the repository's root
+// manifest, then the user's.
+func LoadTagRules(root string, user TagRules) {
+	var repo TagRules
+	if manifest := loadManifestQuietly(root); manifest != nil {
+		repo = manifest.TagRules
+	}
+	UseTagRules(repo, user)
+}
+
+// CanonicalTag returns the canonical form of a tag value.
+func CanonicalTag(value string) string {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	return activeRules.canonical(value)
+}
+
+// mergeTagRules combines rule sets, later ones winning. Keys and values are
+// lower-cased like tags are.
+func mergeTagRules(layers ...TagRules) TagRules {
+	merged := TagRules{Synonyms: make(map[string]string), Parents: make(map[string][]string)}
+	for _, layer := range layers {
+		for from, to := range layer.Synonyms {
+			merged.Synonyms[strings.ToLower(from)] = strings.ToLower(to)
+		}
+		for child, parents := range layer.Parents {
+			lowered := make([]string, len(parents))
+			for i, parent := range parents {
+				lowered[i] = strings.ToLower(parent)
+			}
+			merged.Parents[strings.ToLower(child)] = lowered
+		}
+	}
+	return merged
+}
+
+// canonical follows synonyms to the canonical value, stopping at cycles.
+func (r TagRules) canonical(value string) string {
+	seen := map[string]bool{value: true}
+	for {
+		next, ok := r.Synonyms[value]
+		if !ok || seen[next] {
+			return value
+		}
+		seen[next] = true
+		value = next
+	}
+}
+
+// fingerprint identifies the rules, so tags parsed under other rules can be
+// recognised as stale.
+func (r TagRules) fingerprint() string {
+	data, _ := json.Marshal(r) // Map keys are sorted, so equal rules match
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// tagRulesFingerprint returns the fingerprint of the rules in use.
+func tagRulesFingerprint() string {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	return activeRulesPrint
+}
+
+// normalizeTags replaces values with their canonical form, drops the
+// duplicates that leaves, and adds the parents of each value as implied
+// tags in the same category.
+func normalizeTags(tags []Tag) []Tag {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	normalized := make([]Tag, 0, len(tags))
+	seen := make(map[Tag]bool, len(tags))
+	add := func(tag Tag) {
+		key := Tag{Category: tag.Category, Value: tag.Value}
+		if !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	for _, tag := range tags {
+		if !tag.Implied {
+			tag.Value = activeRules.canonical(tag.Value)
+			add(tag)
+		}
+	}
+	for i := 0; i < len(normalized); i++ {
+		// Parents of parents are added as the loop reaches them
+		for _, parent := range activeRules.Parents[normalized[i].Value] {
+			add(Tag{Category: normalized[i].Category, Value: activeRules.canonical(parent), Implied: true})
+		}
+	}
+	return normalized
+}
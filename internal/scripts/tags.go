@@ -2,6 +2,7 @@ package scripts
 
 import (
 	"bufio"
+	"errors"
 	"os"
 	"regexp"
 	"strings"
@@ -11,12 +12,92 @@ import (
 type Tag struct {
 	Category string
 	Value    string
+	Implied  bool // Added by a parent rule rather than written by the author
 }
 
-// ScriptTags holds all tags for a script
+// ScriptTags holds all tags for a script, along with the descriptive
+// fields of its header
 type ScriptTags struct {
 	Path string
 	Tags []Tag
+
+	Description string   // What the script does
+	Author      string   // Who maintains it
+	Version     string   // Script version
+	MinOS       string   // Oldest supported OS, e.g. "Windows 10" or "Ubuntu 22.04"
+	Requires    []string // Commands the script needs on PATH
+	Runtime     string   // Estimated run time, e.g. "5m"
+	DocsURL     string   // Where to read more
+	Params      []Param  // Values asked for before the script runs
+}
+
+// HasMetadata reports whether any descriptive header field is set.
+func (st *ScriptTags) HasMetadata() bool {
+	return st.Description != "" || st.Author != "" || st.Version != "" || st.MinOS != "" ||
+		len(st.Requires) > 0 || st.Runtime != "" || st.DocsURL != "" || len(st.Params) > 0
+}
+
+// metadataPattern matches descriptive header lines such as
+// "# Author: Jane" or "#! Description: Installs tools".
+var metadataPattern = regexp.MustCompile(`(?i)^#!?\s*(description|author|version|min[-_]?os|requires|runtime|docs(?:[-_]?url)?|param):\s*(.+)$`)
+
+// IsHeaderField reports whether a line is a descriptive header field
+// rather than a tag category.
+func IsHeaderField(line string) bool {
+	return metadataPattern.MatchString(strings.TrimSpace(line))
+}
+
+// setMetadata stores a header field matched by metadataPattern.
+func (st *ScriptTags) setMetadata(key, value string) {
+	value = strings.TrimSpace(value)
+	switch strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key)) {
+	case "description":
+		st.Description = value
+	case "author":
+		st.Author = value
+	case "version":
+		st.Version = value
+	case "minos":
+		st.MinOS = value
+	case "requires":
+		for _, command := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			st.Requires = append(st.Requires, command)
+		}
+	case "runtime":
+		st.Runtime = value
+	case "docs", "docsurl":
+		st.DocsURL = value
+	case "param":
+		// Malformed declarations are skipped here and reported by go-pwr lint
+		if param, err := ParseParam(value); err == nil {
+			st.Params = append(st.Params, param)
+		}
+	}
+}
+
+// headerLines is how far into a file descriptive header fields are read.
+const headerLines = 50
+
+// KnownCategories lists the tag categories documented for scriptbin authors.
+var KnownCategories = []string{
+	"languages",
+	"platforms",
+	"distros",
+	"categories",
+	"packagemanagers",
+	"desktopenvironments",
+	"architectures",
+	"privilege",
+}
+
+// IsKnownCategory reports whether category is one of KnownCategories.
+func IsKnownCategory(category string) bool {
+	for _, known := range KnownCategories {
+		if strings.EqualFold(known, category) {
+			return true
+		}
+	}
+	return false
 }
 
 // TagCategory represents different types of tags
@@ -25,7 +106,8 @@ type TagCategory struct {
 	Values []string
 }
 
-// ParseTags extracts tags from a script file
+// ParseTags extracts tags from the header of a script file. Only the first
+// headerLines lines are read; a line too long to scan ends the header.
 func ParseTags(filePath string) (*ScriptTags, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -33,17 +115,50 @@ func ParseTags(filePath string) (*ScriptTags, error) {
 	}
 	defer file.Close()
 
-	var tags []Tag
+	var lines []string
 	scanner := bufio.NewScanner(file)
+	for len(lines) < headerLines && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	scanErr := scanner.Err()
+	if errors.Is(scanErr, bufio.ErrTooLong) {
+		scanErr = nil
+	}
+	lines = NormalizeHeader(filePath, lines)
+
+	var tags []Tag
+	scriptTags := &ScriptTags{Path: filePath}
+	lineNo := 0
 	inTagsSection := false
+
+	// Front matter is read as a whole and skipped by the line parser below
+	frontMatter := FindFrontMatter(lines)
+	if frontMatter != nil {
+		parsed := &ScriptTags{Path: filePath}
+		if frontMatter.Apply(parsed) == nil {
+			scriptTags = parsed
+		} else {
+			frontMatter = nil // Malformed; reported by go-pwr lint
+		}
+	}
 	
 	// Regex patterns for different tag formats
 	tagsStartPattern := regexp.MustCompile(`^#\*Tags:?\s*$`)
 	categoryPattern := regexp.MustCompile(`^#\s*([A-Za-z_]+):\s*(.+)$`)
 	oldFormatPattern := regexp.MustCompile(`^#([a-zA-Z_]+)\s+#([a-zA-Z_]+)`)
 	
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		lineNo++
+		if frontMatter != nil && lineNo-1 >= frontMatter.Start && lineNo-1 <= frontMatter.End {
+			continue
+		}
+
+		// Descriptive fields may appear above or inside the tags section
+		if matches := metadataPattern.FindStringSubmatch(line); matches != nil {
+			scriptTags.setMetadata(matches[1], matches[2])
+			continue
+		}
 		
 		// Stop parsing after first 50 lines to avoid parsing entire file
 		if len(tags) > 0 && !inTagsSection {
@@ -95,10 +210,8 @@ func ParseTags(filePath string) (*ScriptTags, error) {
 		}
 	}
 	
-	return &ScriptTags{
-		Path: filePath,
-		Tags: tags,
-	}, scanner.Err()
+	scriptTags.Tags = normalizeTags(append(scriptTags.Tags, tags...))
+	return scriptTags, scanErr
 }
 
 // HasTag checks if a script has a specific tag
@@ -164,6 +277,18 @@ func (st *ScriptTags) GetTagsByCategory(category string) []string {
 	return values
 }
 
+// authoredTags returns the values of a category the author wrote, leaving
+// out those implied by parent rules.
+func (st *ScriptTags) authoredTags(category string) []string {
+	var values []string
+	for _, tag := range st.Tags {
+		if !tag.Implied && strings.EqualFold(tag.Category, category) {
+			values = append(values, tag.Value)
+		}
+	}
+	return values
+}
+
 // GetAllCategories returns all unique categories
 func (st *ScriptTags) GetAllCategories() []string {
 	categorySet := make(map[string]bool)
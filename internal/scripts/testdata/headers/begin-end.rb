@@ -0,0 +1,7 @@
+#!/usr/bin/env ruby
+=begin
+*Tags:
+Platforms: linux osx
+Languages: rb
+=end
+puts "hello"
@@ -0,0 +1,7 @@
+<#
+Description: Installs packages with Chocolatey
+*Tags:
+Platforms: windows
+PackageManagers: choco winget
+#>
+Write-Host "hello"
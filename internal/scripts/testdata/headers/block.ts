@@ -0,0 +1,7 @@
+/**
+ * *Tags:
+ * Platforms: linux
+ * Languages: ts
+ * Version: 1.2.0
+ */
+console.log("hello");
@@ -0,0 +1,6 @@
+@echo off
+:: *Tags:
+:: Platforms: win
+:: Privilege: admin
+
+net session
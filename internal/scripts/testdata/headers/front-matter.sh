@@ -0,0 +1,9 @@
+#!/bin/bash
+# ---
+# description: Installs developer tools
+# requires: [git, curl]
+# tags:
+#   platforms: [linux, mac]
+#   distros: ubuntu
+# ---
+echo "hello"
@@ -0,0 +1,4 @@
+#!/bin/bash
+#*Tags:
+#Platforms: linux
+#Categories: setup
@@ -0,0 +1,7 @@
+@echo off
+REM *Tags:
+REM Platforms: windows
+REM Categories: setup, tools
+REM Description: Installs tools with winget
+
+winget install Git.Git
@@ -0,0 +1,7 @@
+#!/usr/bin/env node
+// *Tags:
+// Platforms: linux mac
+// Languages: js
+// Author: Jane Doe
+
+console.log("hello");
@@ -0,0 +1,119 @@
+package scripts
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a burst of file events (a save, a git pull) is
+// collected before it is reported as one change.
+const watchDebounce = 250 * time.Millisecond
+
+// Watcher reports changes to the files below a scripts directory.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	changes chan []string
+	done    chan struct{}
+}
+
+// WatchDir starts watching root and its subdirectories, skipping hidden
+// ones such as .git.
+func WatchDir(root string) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		watcher: watcher,
+		changes: make(chan []string),
+		done:    make(chan struct{}),
+	}
+	if err := w.addTree(root); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers the paths that changed, one batch per burst of events.
+// It is closed when the watcher is.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.watcher.Close()
+}
+
+// addTree watches dir and every non-hidden directory below it.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil // Skip unreadable subdirectories
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return w.watcher.Add(path)
+	})
+}
+
+// run collects events into batches until the watcher is closed.
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	pending := make(map[string]bool)
+	var flush <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if strings.HasPrefix(filepath.Base(event.Name), ".") {
+				continue // Editor swap files, .git and the like
+			}
+			if event.Has(fsnotify.Create) {
+				// New directories need watches of their own; errors just mean
+				// it was not a directory or is already gone
+				w.addTree(event.Name)
+			}
+			pending[event.Name] = true
+			if flush == nil {
+				flush = time.After(watchDebounce)
+			}
+		case <-flush:
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			pending = make(map[string]bool)
+			flush = nil
+			select {
+			case w.changes <- paths:
+			case <-w.done:
+				return
+			}
+		case _, ok := <-w.watcher.Errors:
+			// Overflows and the like; the next event triggers a reload anyway
+			if !ok {
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
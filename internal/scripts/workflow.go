@@ -0,0 +1,258 @@
+package scripts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+	"gopkg.in/yaml.v3"
+)
+
+// What a workflow does when a step fails.
+const (
+	OnErrorStop     = "stop"     // Skip the remaining steps (the default)
+	OnErrorContinue = "continue" // Run the remaining steps anyway
+)
+
+// Workflow is a named sequence of scripts run one after another, defined in
+// the repository's root manifest or the user's config:
+//
+//	workflows:
+//	  new-dev-laptop:
+//	    description: Set up a new developer laptop
+//	    on_error: stop
+//	    params: {git_email: jane@example.com}
+//	    steps:
+//	      - linux/update.sh
+//	      - script: install-tools
+//	        params: {profile: full}
+type Workflow struct {
+	Name        string            `yaml:"-" json:"-"`
+	Description string            `yaml:"description" json:"description,omitempty"`
+	OnError     string            `yaml:"on_error" json:"on_error,omitempty"` // stop or continue
+	Params      map[string]string `yaml:"params" json:"params,omitempty"`     // Shared by every step that declares them
+	Steps       []WorkflowStep    `yaml:"steps" json:"steps"`
+}
+
+// WorkflowStep is one script of a workflow. A step written as a plain
+// string names just the script.
+type WorkflowStep struct {
+	Script string            `yaml:"script" json:"script"`           // Path, file name or alias, as for go-pwr run
+	Args   []string          `yaml:"args" json:"args,omitempty"`     // Extra arguments
+	Params map[string]string `yaml:"params" json:"params,omitempty"` // Override the workflow's params
+}
+
+// UnmarshalYAML accepts a step written as a plain script name.
+func (s *WorkflowStep) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Script = node.Value
+		return nil
+	}
+	type plain WorkflowStep
+	return node.Decode((*plain)(s))
+}
+
+// UnmarshalJSON accepts a step written as a plain script name.
+func (s *WorkflowStep) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.Script); err == nil {
+		return nil
+	}
+	type plain WorkflowStep
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// StopsOnError reports whether a failed step ends the workflow.
+func (w Workflow) StopsOnError() bool {
+	return w.OnError != OnErrorContinue
+}
+
+// LoadWorkflows returns the workflows of the repository's root manifest and
+// the user's, sorted by name. A user workflow replaces a repository one of
+// the same name.
+func LoadWorkflows(root string, user map[string]Workflow) ([]Workflow, error) {
+	manifest, err := LoadManifest(root)
+	byName := make(map[string]Workflow)
+	if manifest != nil {
+		for name, workflow := range manifest.Workflows {
+			byName[name] = workflow
+		}
+	}
+	for name, workflow := range user {
+		byName[name] = workflow
+	}
+
+	workflows := make([]Workflow, 0, len(byName))
+	for name, workflow := range byName {
+		workflow.Name = name
+		workflows = append(workflows, workflow)
+	}
+	sort.Slice(workflows, func(i, j int) bool { return workflows[i].Name < workflows[j].Name })
+	return workflows, err
+}
+
+// FindWorkflow returns the workflow with the given name.
+func FindWorkflow(workflows []Workflow, name string) (Workflow, bool) {
+	for _, workflow := range workflows {
+		if workflow.Name == name {
+			return workflow, true
+		}
+	}
+	return Workflow{}, false
+}
+
+// PlannedStep is a workflow step resolved to a script and the options it
+// runs with.
+type PlannedStep struct {
+	WorkflowStep
+	Item    Item
+	Options platform.RunOptions
+}
+
+// Plan resolves every step of the workflow against the scripts below root
+// and fills in their parameters, so a workflow with a typo fails before
+// anything runs. The error lists every step that could not be planned.
+func (w Workflow) Plan(root string) ([]PlannedStep, error) {
+	if w.OnError != "" && w.OnError != OnErrorStop && w.OnError != OnErrorContinue {
+		return nil, fmt.Errorf("on_error must be %q or %q, not %q", OnErrorStop, OnErrorContinue, w.OnError)
+	}
+	if len(w.Steps) == 0 {
+		return nil, fmt.Errorf("no steps")
+	}
+
+	var steps []PlannedStep
+	var problems []error
+	var items []Item // Listed once, when the first step names a script
+	for i, step := range w.Steps {
+		var item Item
+		var err error
+		if isFilePath(step.Script) {
+			item, err = loadRepositoryFile(root, step.Script)
+		} else {
+			if items == nil {
+				items = allScripts(root)
+			}
+			item, err = findScriptIn(items, root, step.Script)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("step %d: %v", i+1, err))
+			continue
+		}
+
+		values := make(map[string]string)
+		for name, value := range w.Params {
+			values[name] = value
+		}
+		for name, value := range step.Params {
+			values[name] = value
+		}
+		args, env, err := ParamValues(item.Params(), values)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("step %d (%s): %v", i+1, step.Script, err))
+			continue
+		}
+
+		steps = append(steps, PlannedStep{
+			WorkflowStep: step,
+			Item:         item,
+			Options: platform.RunOptions{
+				Args: append(append(append([]string{}, item.Args()...), args...), step.Args...),
+				Env:  env,
+			},
+		})
+	}
+	return steps, errors.Join(problems...)
+}
+
+// StepStatus is how far a workflow step got.
+type StepStatus int
+
+const (
+	StepPending StepStatus = iota
+	StepRunning
+	StepDone
+	StepFailed
+	StepSkipped
+)
+
+// Icon returns the symbol the status is shown with.
+func (s StepStatus) Icon() string {
+	switch s {
+	case StepRunning:
+		return "▶"
+	case StepDone:
+		return "✓"
+	case StepFailed:
+		return "✗"
+	case StepSkipped:
+		return "⏭"
+	}
+	return "○"
+}
+
+// WorkflowRun tracks the steps of a workflow as they run.
+type WorkflowRun struct {
+	Workflow Workflow
+	Steps    []PlannedStep
+	Status   []StepStatus
+	Errors   []error // Why each failed step failed
+}
+
+// NewWorkflowRun starts tracking a planned workflow.
+func NewWorkflowRun(workflow Workflow, steps []PlannedStep) *WorkflowRun {
+	return &WorkflowRun{
+		Workflow: workflow,
+		Steps:    steps,
+		Status:   make([]StepStatus, len(steps)),
+		Errors:   make([]error, len(steps)),
+	}
+}
+
+// Start marks step i as running.
+func (r *WorkflowRun) Start(i int) {
+	r.Status[i] = StepRunning
+}
+
+// Finish records the result of step i and returns the step to run next, or
+// -1 when the run is over. After a failure the remaining steps are skipped
+// unless the workflow continues on errors.
+func (r *WorkflowRun) Finish(i int, err error) int {
+	if err != nil {
+		r.Status[i] = StepFailed
+		r.Errors[i] = err
+		if r.Workflow.StopsOnError() {
+			for j := i + 1; j < len(r.Steps); j++ {
+				r.Status[j] = StepSkipped
+			}
+			return -1
+		}
+	} else {
+		r.Status[i] = StepDone
+	}
+	if i+1 < len(r.Steps) {
+		return i + 1
+	}
+	return -1
+}
+
+// Failed returns how many steps failed.
+func (r *WorkflowRun) Failed() int {
+	failed := 0
+	for _, status := range r.Status {
+		if status == StepFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Running reports whether a step is still running or waiting to run.
+func (r *WorkflowRun) Running() bool {
+	for _, status := range r.Status {
+		if status == StepPending || status == StepRunning {
+			return true
+		}
+	}
+	return false
+}
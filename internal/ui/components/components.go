@@ -14,7 +14,8 @@ import (
 
 // ScriptDelegate handles rendering of script items in lists.
 type ScriptDelegate struct {
-	theme *styles.Theme
+	theme  *styles.Theme
+	pinned map[string]bool // Paths of pinned scripts
 }
 
 // NewScriptDelegate creates a new script delegate.
@@ -22,6 +23,14 @@ func NewScriptDelegate(theme *styles.Theme) *ScriptDelegate {
 	return &ScriptDelegate{theme: theme}
 }
 
+// SetPinned sets the paths of the scripts marked as pinned.
+func (d *ScriptDelegate) SetPinned(paths []string) {
+	d.pinned = make(map[string]bool, len(paths))
+	for _, path := range paths {
+		d.pinned[path] = true
+	}
+}
+
 // Render renders a script item.
 func (d *ScriptDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
 	s, ok := item.(scripts.Item)
@@ -32,6 +41,8 @@ func (d *ScriptDelegate) Render(w io.Writer, m list.Model, index int, item list.
 	var style lipgloss.Style
 	if s.IsDirectory() {
 		style = lipgloss.NewStyle().Foreground(d.theme.Current.Secondary)
+	} else if !s.IsCompatible() {
+		style = lipgloss.NewStyle().Foreground(d.theme.Current.Dim) // Tags exclude this host
 	} else {
 		style = lipgloss.NewStyle().Foreground(d.theme.Current.Primary)
 	}
@@ -40,7 +51,42 @@ func (d *ScriptDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		style = style.Bold(true).Underline(true)
 	}
 
-	fmt.Fprint(w, style.Render(s.Title()))
+	var badge string
+	if s.IsModule() {
+		badge = "📦 " // Submodule pulled in from another repository
+	} else if s.NeedsAdmin() {
+		badge = "🔒 " // Runs with administrator rights
+	}
+
+	// Fuzzy search results highlight the matched characters
+	title := style.Render(s.Title())
+	if matched := s.MatchedRunes(); len(matched) > 0 {
+		title = lipgloss.StyleRunes(s.Title(), matched, style.Foreground(d.theme.Current.Accent), style)
+	}
+
+	// Pinned scripts get a star, so they are recognisable outside Favorites
+	var pin string
+	if d.pinned[s.Description()] && s.IsScript() {
+		pin = lipgloss.NewStyle().Foreground(d.theme.Current.Accent).Render(" ★")
+	}
+
+	// Scripts whose requirements are missing here get a warning marker
+	var warning string
+	if len(s.UnmetRequirements()) > 0 {
+		warning = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(" ⚠")
+	}
+
+	// Content search results show where the first hit is
+	var hint string
+	if matches := s.Matches(); len(matches) > 0 {
+		hint = fmt.Sprintf("  :%d", matches[0].Line)
+		if len(matches) > 1 {
+			hint += fmt.Sprintf(" (+%d)", len(matches)-1)
+		}
+		hint = lipgloss.NewStyle().Foreground(d.theme.Current.Dim).Render(hint)
+	}
+
+	fmt.Fprint(w, style.Render(badge)+title+pin+warning+hint)
 }
 
 func (d *ScriptDelegate) Height() int                             { return 1 }
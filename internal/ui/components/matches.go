@@ -0,0 +1,69 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/internal/ui/styles"
+)
+
+// ContentMatches renders the lines a content search found in a script, with
+// their line numbers and surrounding lines, for the top of the preview pane.
+func ContentMatches(s scripts.Item, term string, theme *styles.Theme) string {
+	matches := s.Matches()
+	if len(matches) == 0 {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Current.Accent)
+	hitStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Current.Primary)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Current.Dim)
+
+	noun := "matches"
+	if len(matches) == 1 {
+		noun = "match"
+	}
+	lines := []string{labelStyle.Render(fmt.Sprintf("🔎 %d %s for %q", len(matches), noun, term))}
+
+	// Collect hits and context by line number, so overlapping context
+	// around nearby hits is printed once
+	type row struct {
+		text string
+		hit  bool
+	}
+	rows := make(map[int]row)
+	for _, match := range matches {
+		for i, text := range match.Before {
+			if _, ok := rows[match.Line-len(match.Before)+i]; !ok {
+				rows[match.Line-len(match.Before)+i] = row{text: text}
+			}
+		}
+		for i, text := range match.After {
+			if _, ok := rows[match.Line+1+i]; !ok {
+				rows[match.Line+1+i] = row{text: text}
+			}
+		}
+		rows[match.Line] = row{text: match.Text, hit: true}
+	}
+	numbers := make([]int, 0, len(rows))
+	for number := range rows {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	for i, number := range numbers {
+		if i > 0 && number > numbers[i-1]+1 {
+			lines = append(lines, dimStyle.Render("  ⋮"))
+		}
+		if r := rows[number]; r.hit {
+			lines = append(lines, hitStyle.Render(fmt.Sprintf("▶ %4d │ %s", number, r.text)))
+		} else {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  %4d │ %s", number, r.text)))
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n" + dimStyle.Render(strings.Repeat("─", 40)) + "\n\n"
+}
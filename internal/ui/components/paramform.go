@@ -0,0 +1,204 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/internal/ui/styles"
+)
+
+// ParamForm asks for the parameters a script declares before it runs.
+type ParamForm struct {
+	title  string
+	params []scripts.Param
+	inputs []textinput.Model
+	errors []string
+	focus  int
+	theme  *styles.Theme
+}
+
+// NewParamForm creates a form for params, pre-filled with their defaults.
+func NewParamForm(theme *styles.Theme, title string, params []scripts.Param) *ParamForm {
+	inputs := make([]textinput.Model, len(params))
+	for i, p := range params {
+		ti := textinput.New()
+		ti.CharLimit = 200
+		ti.Width = 40
+		ti.SetValue(p.Default)
+		switch p.Type {
+		case scripts.ParamEnum:
+			ti.Placeholder = strings.Join(p.Options, " | ")
+		case scripts.ParamBool:
+			ti.Placeholder = "true | false"
+		default:
+			ti.Placeholder = p.Type
+		}
+		inputs[i] = ti
+	}
+
+	f := &ParamForm{
+		title:  title,
+		params: params,
+		inputs: inputs,
+		errors: make([]string, len(params)),
+		theme:  theme,
+	}
+	f.setFocus(0)
+	return f
+}
+
+// SetWidth adjusts the width of the inputs.
+func (f *ParamForm) SetWidth(width int) {
+	if width < 20 {
+		width = 20
+	}
+	if width > 80 {
+		width = 80
+	}
+	for i := range f.inputs {
+		f.inputs[i].Width = width
+	}
+}
+
+// setFocus moves the cursor to the input at index.
+func (f *ParamForm) setFocus(index int) {
+	f.inputs[f.focus].Blur()
+	f.focus = index
+	f.inputs[f.focus].Focus()
+}
+
+// Update handles a key press. It returns true once the last field is
+// confirmed and every value is valid.
+func (f *ParamForm) Update(msg tea.Msg) (bool, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+		return false, cmd
+	}
+
+	switch keyMsg.String() {
+	case "tab", "down":
+		f.setFocus((f.focus + 1) % len(f.inputs))
+		return false, nil
+	case "shift+tab", "up":
+		f.setFocus((f.focus - 1 + len(f.inputs)) % len(f.inputs))
+		return false, nil
+	case "enter":
+		if f.focus < len(f.inputs)-1 {
+			f.setFocus(f.focus + 1)
+			return false, nil
+		}
+		return f.Validate(), nil
+	case "left", "right":
+		// Choices cycle through their options instead of moving the cursor
+		if options := f.choices(f.params[f.focus]); options != nil {
+			f.cycle(options, keyMsg.String() == "right")
+			return false, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	f.errors[f.focus] = ""
+	return false, cmd
+}
+
+// choices returns the values a parameter can cycle through, or nil for
+// free-text parameters.
+func (f *ParamForm) choices(p scripts.Param) []string {
+	switch p.Type {
+	case scripts.ParamEnum:
+		return p.Options
+	case scripts.ParamBool:
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+// cycle selects the next or previous option of the focused input.
+func (f *ParamForm) cycle(options []string, forward bool) {
+	current := -1
+	for i, option := range options {
+		if strings.EqualFold(option, f.inputs[f.focus].Value()) {
+			current = i
+			break
+		}
+	}
+	next := 0
+	if forward {
+		next = (current + 1) % len(options)
+	} else if current > 0 {
+		next = current - 1
+	} else {
+		next = len(options) - 1
+	}
+	f.inputs[f.focus].SetValue(options[next])
+	f.inputs[f.focus].CursorEnd()
+	f.errors[f.focus] = ""
+}
+
+// Validate checks every value, recording errors next to the fields, and
+// moves the cursor to the first invalid one.
+func (f *ParamForm) Validate() bool {
+	valid := true
+	for i, p := range f.params {
+		f.errors[i] = ""
+		if _, err := p.Validate(f.inputs[i].Value()); err != nil {
+			f.errors[i] = err.Error()
+			if valid {
+				f.setFocus(i)
+			}
+			valid = false
+		}
+	}
+	return valid
+}
+
+// Values returns the entered values keyed by parameter name.
+func (f *ParamForm) Values() map[string]string {
+	values := make(map[string]string, len(f.params))
+	for i, p := range f.params {
+		values[p.Name] = f.inputs[i].Value()
+	}
+	return values
+}
+
+// View renders the form.
+func (f *ParamForm) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(f.theme.Current.Primary)
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(f.theme.Current.Accent)
+	dimStyle := lipgloss.NewStyle().Foreground(f.theme.Current.Dim)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("▶ Run " + f.title))
+	b.WriteString("\n\n")
+
+	for i, p := range f.params {
+		label := p.Name
+		if p.Required {
+			label += " *"
+		}
+		if i == f.focus {
+			label = "› " + label
+		} else {
+			label = "  " + label
+		}
+		b.WriteString(labelStyle.Render(label))
+		if p.Description != "" {
+			b.WriteString(" " + dimStyle.Render(p.Description))
+		}
+		b.WriteString("\n  " + f.inputs[i].View() + "\n")
+		if f.errors[i] != "" {
+			b.WriteString("  " + errorStyle.Render("❌ "+f.errors[i]) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(dimStyle.Render("* required"))
+	return b.String()
+}
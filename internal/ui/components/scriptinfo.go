@@ -0,0 +1,97 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/internal/ui/styles"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// ScriptInfo renders the description and header fields of a script for the
+// top of the preview pane. It returns an empty string if there are none.
+func ScriptInfo(s scripts.Item, theme *styles.Theme) string {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Current.Accent)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Current.Dim)
+
+	var lines []string
+	if reason := s.Incompatibility(); reason != "" {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214")).Render("⚠ Not compatible with this host ("+reason+")"))
+	}
+	if unmet := s.UnmetRequirements(); len(unmet) > 0 {
+		lines = append(lines, MissingRequirements(unmet, theme))
+	}
+	switch s.Privilege() {
+	case scripts.PrivilegeAdmin:
+		lines = append(lines, labelStyle.Render("🔒 Requires administrator rights"))
+	case scripts.PrivilegeUser:
+		lines = append(lines, labelStyle.Render("👤 Must not run as root"))
+	}
+	if summary := s.Summary(); summary != "" {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(theme.Current.Primary).Render(summary))
+	}
+
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, labelStyle.Render(label+": ")+value)
+		}
+	}
+	if tags := s.GetTags(); tags != nil {
+		field("Author", tags.Author)
+		field("Version", tags.Version)
+		field("Min OS", tags.MinOS)
+		field("Requires", strings.Join(tags.Requires, ", "))
+		field("Runtime", tags.Runtime)
+		field("Docs", tags.DocsURL)
+		if len(tags.Params) > 0 {
+			lines = append(lines, labelStyle.Render("Parameters:"))
+			for _, p := range tags.Params {
+				lines = append(lines, "  "+describeParam(p))
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n" + dimStyle.Render(strings.Repeat("─", 40)) + "\n\n"
+}
+
+// MissingRequirements lists requirements the host does not meet, each with
+// the commands that would install it.
+func MissingRequirements(unmet []platform.Unmet, theme *styles.Theme) string {
+	warnStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Current.Dim)
+
+	lines := []string{warnStyle.Render("⚠ Missing requirements:")}
+	for _, u := range unmet {
+		lines = append(lines, "  • "+u.Reason)
+		for _, hint := range u.InstallHints() {
+			lines = append(lines, dimStyle.Render("      "+hint))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeParam summarizes a parameter on one line, e.g.
+// "env (dev|prod, default dev) - Target environment".
+func describeParam(p scripts.Param) string {
+	kind := p.Type
+	if p.Type == scripts.ParamEnum {
+		kind = strings.Join(p.Options, "|")
+	}
+	details := []string{kind}
+	if p.Required {
+		details = append(details, "required")
+	}
+	if p.Default != "" {
+		details = append(details, "default "+p.Default)
+	}
+
+	text := p.Name + " (" + strings.Join(details, ", ") + ")"
+	if p.Description != "" {
+		text += " - " + p.Description
+	}
+	return text
+}
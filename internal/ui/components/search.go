@@ -3,20 +3,31 @@ package components
 import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/rocketpowerinc/go-pwr/internal/ui/styles"
 )
 
+// Search modes of the search box, cycled with Tab.
+const (
+	SearchTags    = iota // Tag query language
+	SearchContent        // Full text of the scripts
+	SearchFuzzy          // Fuzzy match on names and relative paths
+	searchModes
+)
+
 // SearchInput represents a search input component
 type SearchInput struct {
 	textInput textinput.Model
 	theme     *styles.Theme
 	active    bool
+	errorMsg  string // Why the current query cannot be parsed
+	mode      int    // One of the Search* modes
 }
 
 // NewSearchInput creates a new search input component
 func NewSearchInput(theme *styles.Theme) *SearchInput {
 	ti := textinput.New()
-	ti.Placeholder = "Search by tags (e.g., bash linux ubuntu)..."
+	ti.Placeholder = "Search by tags (e.g., platform:linux (apt OR dnf) -wsl)..."
 	ti.CharLimit = 100
 	ti.Width = 50 // Default width, will be adjusted dynamically
 	
@@ -42,12 +53,13 @@ func (si *SearchInput) SetWidth(width int) {
 
 // ViewMinimal renders a minimal search input for very small spaces
 func (si *SearchInput) ViewMinimal() string {
+	prefix := si.prefix()
 	value := si.textInput.Value()
 	if value == "" {
 		if si.active {
-			return "🔍 Search..."
+			return prefix + "Search..."
 		}
-		return "🔍 Search"
+		return prefix + "Search"
 	}
 	
 	// Truncate if too long
@@ -56,9 +68,9 @@ func (si *SearchInput) ViewMinimal() string {
 	}
 	
 	if si.active {
-		return "🔍 " + value + "_"
+		return prefix + value + "_"
 	}
-	return "🔍 " + value
+	return prefix + value
 }
 
 // SetActive sets whether the search input is active
@@ -87,26 +99,55 @@ func (si *SearchInput) Update(msg tea.Msg) tea.Cmd {
 
 // View renders the search input
 func (si *SearchInput) View() string {
+	if si.errorMsg != "" {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true)
+		return si.view() + "\n" + errorStyle.Render("❌ "+si.errorMsg)
+	}
+	return si.view()
+}
+
+// view renders the search input without the error message.
+func (si *SearchInput) view() string {
 	// Simple approach: just show the prefix and the actual text value, no fancy styling
-	prefix := "🔍 "
+	prefix := si.prefix()
+	target := "tags"
+	switch si.mode {
+	case SearchContent:
+		target = "script contents"
+	case SearchFuzzy:
+		target = "name"
+	}
 	
 	if si.active {
 		// When active, show the value and cursor
 		value := si.textInput.Value()
 		if value == "" {
-			return prefix + "Search by tags..."
+			return prefix + "Search by " + target + "... ('Tab' to switch)"
 		}
 		return prefix + value + "_"
 	} else {
 		// When inactive, just show the value or placeholder
 		value := si.textInput.Value()
 		if value == "" {
-			return prefix + "Search by tags"
+			return prefix + "Search by " + target
 		}
 		return prefix + value
 	}
 }
 
+// prefix returns the icon in front of the search text, which shows the mode.
+func (si *SearchInput) prefix() string {
+	switch si.mode {
+	case SearchContent:
+		return "📄 "
+	case SearchFuzzy:
+		return "⚡ "
+	}
+	return "🔍 "
+}
+
 // Value returns the current input value
 func (si *SearchInput) Value() string {
 	return si.textInput.Value()
@@ -120,4 +161,21 @@ func (si *SearchInput) SetValue(value string) {
 // Reset clears the input
 func (si *SearchInput) Reset() {
 	si.textInput.SetValue("")
+	si.errorMsg = ""
+}
+
+// NextMode switches to the next search mode.
+func (si *SearchInput) NextMode() {
+	si.mode = (si.mode + 1) % searchModes
+	si.errorMsg = ""
+}
+
+// Mode returns the current search mode.
+func (si *SearchInput) Mode() int {
+	return si.mode
+}
+
+// SetError shows why the query cannot be used, or clears it if empty.
+func (si *SearchInput) SetError(errorMsg string) {
+	si.errorMsg = errorMsg
 }
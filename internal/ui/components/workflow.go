@@ -0,0 +1,80 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/internal/ui/styles"
+)
+
+// WorkflowItems lists workflows for the Workflows tab.
+func WorkflowItems(workflows []scripts.Workflow) []list.Item {
+	items := make([]list.Item, len(workflows))
+	for i, workflow := range workflows {
+		items[i] = OptionItem{
+			Name:   workflow.Name,
+			Desc:   workflow.Description,
+			Action: "workflow",
+		}
+	}
+	return items
+}
+
+// WorkflowStatus renders a workflow and its steps. While run is a run of the
+// workflow, each step shows how far it got.
+func WorkflowStatus(workflow scripts.Workflow, run *scripts.WorkflowRun, theme *styles.Theme) string {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Current.Accent)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Current.Dim)
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(theme.Current.Primary).Render(workflow.Name)}
+	if workflow.Description != "" {
+		lines = append(lines, workflow.Description)
+	}
+	onError := "stop at the first failed step"
+	if !workflow.StopsOnError() {
+		onError = "continue after failed steps"
+	}
+	lines = append(lines, labelStyle.Render("On error: ")+onError)
+	if len(workflow.Params) > 0 {
+		var params []string
+		for name, value := range workflow.Params {
+			params = append(params, name+"="+value)
+		}
+		sort.Strings(params)
+		lines = append(lines, labelStyle.Render("Params: ")+strings.Join(params, ", "))
+	}
+
+	if run != nil && run.Workflow.Name != workflow.Name {
+		run = nil
+	}
+	lines = append(lines, "", labelStyle.Render("Steps:"))
+	for i, step := range workflow.Steps {
+		status := scripts.StepPending
+		if run != nil && i < len(run.Status) {
+			status = run.Status[i]
+		}
+		line := fmt.Sprintf("  %s %d. %s", status.Icon(), i+1, step.Script)
+		if len(step.Args) > 0 {
+			line += dimStyle.Render(" " + strings.Join(step.Args, " "))
+		}
+		lines = append(lines, line)
+		if run != nil && i < len(run.Errors) && run.Errors[i] != nil {
+			lines = append(lines, failStyle.Render("      "+run.Errors[i].Error()))
+		}
+	}
+
+	if run != nil && !run.Running() {
+		lines = append(lines, "")
+		if failed := run.Failed(); failed > 0 {
+			lines = append(lines, failStyle.Render(fmt.Sprintf("✗ %d of %d steps failed", failed, len(run.Steps))))
+		} else {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("✓ All %d steps succeeded", len(run.Steps))))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,10 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -62,6 +66,13 @@ type Model struct {
 	// Search
 	searchInput  *components.SearchInput
 	searchActive bool
+
+	// Content search reads files, so it runs in the background once typing
+	// pauses; every search bumps searchSeq so stale results are dropped
+	searchSeq        int
+	contentSearchDue bool
+	scopePath        string      // Directory scopeItems were listed for
+	scopeItems       []list.Item // Every script below scopePath
 	recursiveMode bool // Toggle for recursive vs directory view
 
 	// Repository input
@@ -69,6 +80,31 @@ type Model struct {
 	repositoryInputActive bool
 	repositoryViewActive  bool // For "Current Repository" view
 	repositoryResetActive bool // For "Reset to Default" confirmation/result
+	syncCancel            context.CancelFunc // Cancels an in-progress repository sync
+
+	// Parameter form shown before running a script that declares parameters
+	paramForm       *components.ParamForm
+	paramFormActive bool
+	paramFormItem   scripts.Item
+
+	// Script whose requirements are being checked before it runs
+	checkingPath string
+
+	// Confirmation shown before running a script with administrator rights
+	confirmActive bool
+	confirmItem   scripts.Item
+	confirmOpts   platform.RunOptions
+
+	// Workflows tab
+	workflows       []scripts.Workflow
+	workflowItems   []list.Item
+	workflowRun     *scripts.WorkflowRun // The latest run, finished or not
+	workflowConfirm bool                 // Waiting for 'y' to run steps with administrator rights
+	workflowPlan    string               // Workflow being planned and checked before it runs
+
+	// Live reload of the scripts directory
+	watcher      *scripts.Watcher
+	scriptsStale bool // Files changed while the list could not be refreshed
 
 	// Delegates
 	scriptDelegate   *components.ScriptDelegate
@@ -89,7 +125,7 @@ func NewModel(cfg *config.Config) *Model {
 	categoryDelegate := components.NewCategoryDelegate(theme)
 
 	// Get initial script items
-	scriptItems := scripts.GetItems(cfg.ScriptbinPath)
+	scriptItems := loadItems(cfg, cfg.ScriptbinPath)
 
 	// Create option categories
 	optionCategories := []list.Item{
@@ -135,6 +171,9 @@ func NewModel(cfg *config.Config) *Model {
 		},
 	}
 
+	// Load workflows; a broken manifest just leaves the tab empty
+	workflows, _ := scripts.LoadWorkflows(cfg.ScriptbinPath, cfg.Workflows)
+
 	// Create lists
 	scriptList := components.CreateList(scriptItems, scriptDelegate)
 	categoryList := components.CreateList(optionCategories, categoryDelegate)
@@ -150,18 +189,21 @@ func NewModel(cfg *config.Config) *Model {
 	// Create repository input
 	repositoryInput := components.NewRepositoryInput(theme)
 
+	// Watch the scripts for edits; without a watcher there is just no live reload
+	watcher, _ := scripts.WatchDir(cfg.ScriptbinPath)
+
 	// Set initial preview if there are scripts
 	if len(scriptItems) > 0 {
 		if s, ok := scriptItems[0].(scripts.Item); ok && s.IsScript() {
-			content := scripts.ReadContentWithHighlighting(s.Description(), cache)
+			content := previewContent(s, cache, theme)
 			vp.SetContent(content)
 		}
 	}
 
-	return &Model{
+	m := &Model{
 		config:            cfg,
 		theme:             theme,
-		tabs:              []string{"Scripts", "Options", "About"},
+		tabs:              []string{"Scripts", "Workflows", "Options", "About"},
 		activeTab:         0,
 		focus:             FocusList,
 		currentPath:       cfg.ScriptbinPath,
@@ -177,6 +219,8 @@ func NewModel(cfg *config.Config) *Model {
 		optionCategories:  optionCategories,
 		colorSchemeItems:  colorSchemeItems,
 		repositoryItems:   repositoryItems,
+		workflows:         workflows,
+		workflowItems:     components.WorkflowItems(workflows),
 		searchInput:           searchInput,
 		searchActive:          false,
 		recursiveMode:         false, // Start in directory mode
@@ -185,9 +229,12 @@ func NewModel(cfg *config.Config) *Model {
 		repositoryViewActive:  false,
 		repositoryResetActive: false,
 		scriptDelegate:        scriptDelegate,
+		watcher:               watcher,
 		optionDelegate:    optionDelegate,
 		categoryDelegate:  categoryDelegate,
 	}
+	m.updatePins()
+	return m
 }
 
 // Start starts the UI.
@@ -199,12 +246,16 @@ func Start(cfg *config.Config) error {
 		tea.WithMouseAllMotion(),
 	)
 	
-	return program.Start()
+	err := program.Start()
+
+	// Keep the preview cache numbers for go-pwr doctor
+	scripts.SaveCacheStats(scripts.CacheStatsPath(cfg.CacheDir), model.cache.Stats())
+	return err
 }
 
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(waitForScriptChanges(m.watcher), waitForRequirementProbes())
 }
 
 // setSizes sets the sizes of UI components based on window dimensions.
@@ -285,18 +336,32 @@ func (m *Model) switchTab(tabIndex int) {
 	m.repositoryInput.SetActive(false)
 	m.repositoryViewActive = false
 	m.repositoryResetActive = false
+	m.closeParamForm()
+	m.closeConfirm()
+	if m.workflowConfirm {
+		m.workflowConfirm = false
+		m.workflowRun = nil
+	}
 
 	switch tabIndex {
 	case 0: // Scripts tab
 		m.list.SetDelegate(m.scriptDelegate)
 		m.list.SetItems(m.scriptItems)
+		if m.scriptsStale {
+			m.reloadChangedScripts(nil)
+			return
+		}
 		if sel, ok := m.list.SelectedItem().(scripts.Item); ok && sel.IsScript() {
-			content := scripts.ReadContentWithHighlighting(sel.Description(), m.cache)
+			content := previewContent(sel, m.cache, m.theme)
 			m.vp.SetContent(content)
 		} else {
 			m.vp.SetContent("Select a script to preview...")
 		}
-	case 1: // Options tab
+	case 1: // Workflows tab
+		m.list.SetDelegate(m.optionDelegate)
+		m.list.SetItems(m.workflowItems)
+		m.updateWorkflowPreview()
+	case 2: // Options tab
 		m.list.SetDelegate(m.categoryDelegate)
 		// Copy items from categoryList to main list for display
 		items := make([]list.Item, len(m.optionCategories))
@@ -304,7 +369,7 @@ func (m *Model) switchTab(tabIndex int) {
 		m.list.SetItems(items)
 		m.selectedCategory = ""
 		m.vp.SetContent("Select an option category from the left to see available settings.")
-	case 2: // About tab
+	case 3: // About tab
 		m.vp.SetContent("A cross-platform script browser powered by RocketPowerInc.")
 	}
 }
@@ -317,7 +382,7 @@ func (m *Model) navigateIntoDirectory(item scripts.Item) {
 
 	m.parentPaths = append(m.parentPaths, ParentNav{Path: m.currentPath, Index: m.list.Index()})
 	m.currentPath = item.Description()
-	newItems := scripts.GetItems(item.Description())
+	newItems := loadItems(m.config, item.Description())
 	m.scriptItems = newItems
 	m.allScriptItems = newItems // Update backup as well
 	m.list.SetItems(m.scriptItems)
@@ -330,7 +395,7 @@ func (m *Model) navigateIntoDirectory(item scripts.Item) {
 	// Show preview for first item if it's a script
 	if len(m.scriptItems) > 0 {
 		if first, ok := m.scriptItems[0].(scripts.Item); ok && first.IsScript() {
-			content := scripts.ReadContentWithHighlighting(first.Description(), m.cache)
+			content := previewContent(first, m.cache, m.theme)
 			m.vp.SetContent(content)
 		} else {
 			m.vp.SetContent("Select a script to preview...")
@@ -348,7 +413,7 @@ func (m *Model) navigateToParent() {
 
 	parent := m.parentPaths[len(m.parentPaths)-1]
 	m.parentPaths = m.parentPaths[:len(m.parentPaths)-1]
-	newItems := scripts.GetItems(parent.Path)
+	newItems := loadItems(m.config, parent.Path)
 	m.scriptItems = newItems
 	m.allScriptItems = newItems // Update backup as well
 	m.list.SetItems(m.scriptItems)
@@ -361,22 +426,204 @@ func (m *Model) navigateToParent() {
 
 	// Show preview for selected item
 	if sel, ok := m.list.SelectedItem().(scripts.Item); ok && sel.IsScript() {
-		content := scripts.ReadContentWithHighlighting(sel.Description(), m.cache)
+		content := previewContent(sel, m.cache, m.theme)
 		m.vp.SetContent(content)
 	} else {
 		m.vp.SetContent("Select a script to preview...")
 	}
 }
 
-// executeScript runs the selected script.
-func (m *Model) executeScript(item scripts.Item) {
+// loadItems lists a directory, honouring the compatible-only setting. The
+// repository root starts with the Favorites folder when scripts are pinned.
+func loadItems(cfg *config.Config, path string) []list.Item {
+	if path == scripts.FavoritesPath(cfg.ScriptbinPath) {
+		return visibleItems(cfg, scripts.Favorites(cfg.ScriptbinPath, cfg.Favorites))
+	}
+	items := visibleItems(cfg, scripts.GetItems(path))
+	if path == cfg.ScriptbinPath && len(cfg.Favorites) > 0 {
+		items = append([]list.Item{scripts.FavoritesItem(cfg.ScriptbinPath)}, items...)
+	}
+	return items
+}
+
+// visibleItems drops scripts that cannot run on this host when the
+// compatible-only toggle is on.
+func visibleItems(cfg *config.Config, items []list.Item) []list.Item {
+	if !cfg.CompatibleOnly {
+		return items
+	}
+	return scripts.FilterCompatible(items)
+}
+
+// previewContent returns the preview pane text for a script: its
+// description and header fields followed by the highlighted source.
+func previewContent(item scripts.Item, cache *scripts.Cache, theme *styles.Theme) string {
+	return components.ScriptInfo(item, theme) + scripts.ReadContentWithHighlighting(item.Description(), cache)
+}
+
+// executeScript runs the selected script, first asking for its parameters
+// if it declares any.
+func (m *Model) executeScript(item scripts.Item) tea.Cmd {
 	if !item.IsScript() {
+		return nil
+	}
+
+	if interp, ok := platform.ResolveInterpreter(item.Description()); ok && !interp.Available() {
+		m.vp.SetContent(fmt.Sprintf("❌ Cannot run %s: %s is not installed.\n\n%s scripts need %s on your PATH.",
+			item.Title(), interp.Program(), interp.Name, interp.Program()))
+		return nil
+	}
+
+	if len(item.Requires()) == 0 {
+		m.continueScript(item, nil)
+		return nil
+	}
+
+	// Check requirements afresh; something may have been installed since
+	// the list was loaded. Probing versions can take seconds, so it runs
+	// off the UI goroutine.
+	m.checkingPath = item.Description()
+	m.vp.SetContent(fmt.Sprintf("Checking what %s requires...", item.Title()))
+	return func() tea.Msg {
+		platform.ForgetRequirementChecks(item.Requires())
+		return requirementsCheckedMsg{item: item, unmet: platform.CheckRequirements(item.Requires())}
+	}
+}
+
+// requirementsCheckedMsg reports the requirements of a script about to run
+// that this host does not meet.
+type requirementsCheckedMsg struct {
+	item  scripts.Item
+	unmet []platform.Unmet
+}
+
+// finishRequirementsCheck continues running the script whose requirements
+// were checked, unless the user moved on meanwhile.
+func (m *Model) finishRequirementsCheck(msg requirementsCheckedMsg) {
+	if msg.item.Description() != m.checkingPath {
+		return
+	}
+	m.checkingPath = ""
+	if m.activeTab != 0 || m.confirmActive || m.paramFormActive {
+		return
+	}
+	if sel, ok := m.list.SelectedItem().(scripts.Item); !ok || sel.Description() != msg.item.Description() {
+		return
+	}
+	m.continueScript(msg.item, msg.unmet)
+}
+
+// continueScript runs a script whose requirements were checked, asking for
+// its parameters first if it declares any.
+func (m *Model) continueScript(item scripts.Item, unmet []platform.Unmet) {
+	if len(unmet) > 0 {
+		m.vp.SetContent(fmt.Sprintf("❌ Cannot run %s yet.\n\n%s\n\nInstall what is missing, then press Enter again.",
+			item.Title(), components.MissingRequirements(unmet, m.theme)))
+		return
+	}
+
+	if err := m.checkPrivilege(item); err != nil {
+		m.vp.SetContent("❌ " + err.Error())
+		return
+	}
+
+	if params := item.Params(); len(params) > 0 {
+		m.paramForm = components.NewParamForm(m.theme, item.Title(), params)
+		m.paramFormActive = true
+		m.paramFormItem = item
+		return
+	}
+
+	m.confirmOrLaunch(item, platform.RunOptions{Args: item.Args()})
+}
+
+// submitParamForm runs the script the parameter form was opened for.
+func (m *Model) submitParamForm() {
+	item := m.paramFormItem
+	args, env, err := scripts.ParamValues(item.Params(), m.paramForm.Values())
+	m.closeParamForm()
+	if err != nil {
+		m.vp.SetContent("❌ " + err.Error())
+		return
+	}
+	m.confirmOrLaunch(item, platform.RunOptions{Args: append(append([]string{}, item.Args()...), args...), Env: env})
+}
+
+// closeParamForm hides the parameter form.
+func (m *Model) closeParamForm() {
+	m.paramForm = nil
+	m.paramFormActive = false
+	m.paramFormItem = scripts.Item{}
+}
+
+// checkPrivilege reports why a script cannot run with the rights go-pwr has:
+// user-only scripts are refused as root, and admin scripts need sudo or doas
+// when go-pwr is not elevated itself.
+func (m *Model) checkPrivilege(item scripts.Item) error {
+	switch item.Privilege() {
+	case scripts.PrivilegeUser:
+		if platform.IsElevated() {
+			return fmt.Errorf("%s must not run as root. Restart go-pwr as a regular user to run it.", item.Title())
+		}
+	case scripts.PrivilegeAdmin:
+		if !platform.IsElevated() {
+			if _, err := platform.ElevationCommand(m.config.ElevateCommand); err != nil {
+				return fmt.Errorf("%s needs administrator rights, but %v", item.Title(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// confirmOrLaunch runs a script, asking first if it needs administrator
+// rights.
+func (m *Model) confirmOrLaunch(item scripts.Item, opts platform.RunOptions) {
+	if !item.NeedsAdmin() {
+		m.launchScript(item, opts)
 		return
 	}
 
+	how := "with the current administrator rights"
+	if !platform.IsElevated() {
+		elevate, err := platform.ElevationCommand(m.config.ElevateCommand)
+		if err != nil {
+			m.vp.SetContent(fmt.Sprintf("❌ %s needs administrator rights, but %v", item.Title(), err))
+			return
+		}
+		opts.Elevate = elevate
+		how = "via " + elevate
+	}
+
+	m.confirmActive = true
+	m.confirmItem = item
+	m.confirmOpts = opts
+	m.vp.SetContent(fmt.Sprintf("🔒 %s requires administrator rights.\n\nIt will run %s and can change your system.\n\nPress 'y' to run it, 'n' or 'Esc' to cancel.",
+		item.Title(), how))
+}
+
+// confirmLaunch runs the script waiting for confirmation.
+func (m *Model) confirmLaunch() {
+	item, opts := m.confirmItem, m.confirmOpts
+	m.closeConfirm()
+	m.launchScript(item, opts)
+}
+
+// closeConfirm dismisses the administrator rights confirmation.
+func (m *Model) closeConfirm() {
+	wasActive := m.confirmActive
+	m.confirmActive = false
+	m.confirmItem = scripts.Item{}
+	m.confirmOpts = platform.RunOptions{}
+	if wasActive && m.scriptsStale && m.activeTab == 0 {
+		m.reloadChangedScripts(nil)
+	}
+}
+
+// launchScript starts a script in a new terminal window.
+func (m *Model) launchScript(item scripts.Item, opts platform.RunOptions) {
 	m.vp.SetContent("Running script in a new terminal window...")
 	go func() {
-		if err := platform.ExecuteScript(item.Description(), item.Title()); err != nil {
+		if err := platform.ExecuteScriptWithOptions(item.Description(), item.Title(), opts); err != nil {
 			// Could add error handling here, maybe show error in UI
 		}
 	}()
@@ -389,13 +636,36 @@ func (m *Model) updatePreview() {
 	}
 
 	if sel, ok := m.list.SelectedItem().(scripts.Item); ok && sel.IsScript() {
-		content := scripts.ReadContentWithHighlighting(sel.Description(), m.cache)
+		content := previewContent(sel, m.cache, m.theme)
+		if matches := sel.Matches(); len(matches) > 0 {
+			// Content search result: list the hits, then scroll the source
+			// to the first one, keeping a line above it in view
+			hits := components.ContentMatches(sel, strings.TrimSpace(m.searchInput.Value()), m.theme)
+			m.vp.SetContent(hits + content)
+			source := strings.Count(hits+components.ScriptInfo(sel, m.theme), "\n")
+			m.vp.SetYOffset(max(source+matches[0].Line-2, 0))
+			return
+		}
 		m.vp.SetContent(content)
+		m.vp.GotoTop() // Undo the scroll to a previous script's hits
 	} else {
 		m.vp.SetContent("Select a script to preview...")
 	}
 }
 
+// usingMirror reports whether the scripts were synced from a mirror.
+func (m *Model) usingMirror() bool {
+	return m.config.ActiveRepoURL != "" && m.config.ActiveRepoURL != m.config.RepoURL
+}
+
+// mirrorNotice returns a note about the mirror in use, if any.
+func (m *Model) mirrorNotice() string {
+	if !m.usingMirror() {
+		return ""
+	}
+	return "\n\n🔁 The primary repository was unreachable, scripts were loaded from mirror:\n" + m.config.ActiveRepoURL
+}
+
 // applyColorScheme applies a color scheme and saves the preference.
 func (m *Model) applyColorScheme(schemeName string) {
 	schemes := styles.AllSchemes()
@@ -414,21 +684,167 @@ func (m *Model) applyColorScheme(schemeName string) {
 	}
 }
 
-// refreshRepository refreshes the repository and reloads scripts immediately.
-func (m *Model) refreshRepository() error {
-	// Ensure repository is cloned/updated with new config
-	if err := git.EnsureRepository(m.config); err != nil {
-		return err
+// repositorySyncedMsg reports the result of a background repository sync.
+type repositorySyncedMsg struct {
+	action string // The repository action that started the sync
+	cfg    config.Config
+	err    error
+}
+
+// startRepositorySync syncs the repository in the background so the UI
+// stays responsive. Ctrl+C cancels the sync while it is running.
+func (m *Model) startRepositorySync(action string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.syncCancel = cancel
+	cfg := *m.config // Work on a copy; the result is applied in Update
+	return func() tea.Msg {
+		err := git.EnsureRepository(ctx, &cfg)
+		cancel()
+		if err == nil {
+			// Parse what the sync changed now, off the UI goroutine, with
+			// the tag rules the repository may have changed
+			scripts.LoadTagRules(cfg.ScriptbinPath, scripts.TagRules{Synonyms: cfg.TagSynonyms, Parents: cfg.TagParents})
+			scripts.RefreshIndex(cfg.ScriptbinPath)
+		}
+		return repositorySyncedMsg{action: action, cfg: cfg, err: err}
+	}
+}
+
+// scriptsChangedMsg reports files that changed below the scripts directory.
+type scriptsChangedMsg struct {
+	paths   []string
+	watcher *scripts.Watcher // Which watcher saw them, to ignore replaced ones
+}
+
+// waitForScriptChanges waits for the next batch of changes from w.
+func waitForScriptChanges(w *scripts.Watcher) tea.Cmd {
+	if w == nil {
+		return nil
 	}
+	return func() tea.Msg {
+		paths, ok := <-w.Changes()
+		if !ok {
+			return nil
+		}
+		return scriptsChangedMsg{paths: paths, watcher: w}
+	}
+}
+
+// requirementsProbedMsg reports that queued version probes have run.
+type requirementsProbedMsg struct {
+	unmet bool // Whether a listed script turned out to miss a requirement
+}
+
+// waitForRequirementProbes runs version probes as listings queue them, off
+// the UI goroutine since each can take seconds.
+func waitForRequirementProbes() tea.Cmd {
+	return func() tea.Msg {
+		<-platform.RequirementProbesQueued()
+		return requirementsProbedMsg{unmet: platform.ProbeRequirements()}
+	}
+}
 
+// watchScripts watches the current scripts directory instead of the
+// previous one.
+func (m *Model) watchScripts() tea.Cmd {
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	m.watcher, _ = scripts.WatchDir(m.config.ScriptbinPath)
+	return waitForScriptChanges(m.watcher)
+}
+
+// reloadChangedScripts drops cached content and index entries of changed
+// files and refreshes the list and preview in place, keeping the selection,
+// search and scroll position. While the list is not shown or a confirmation
+// is waiting, the refresh is postponed.
+func (m *Model) reloadChangedScripts(paths []string) {
+	for _, path := range paths {
+		m.cache.Invalidate(path)
+		scripts.ForgetIndexed(path)
+		if filepath.Dir(path) == m.config.ScriptbinPath && slices.Contains(scripts.ManifestFiles, filepath.Base(path)) {
+			// The root manifest holds the repository's tag rules and workflows
+			scripts.LoadTagRules(m.config.ScriptbinPath, scripts.TagRules{Synonyms: m.config.TagSynonyms, Parents: m.config.TagParents})
+			m.reloadWorkflows()
+		}
+	}
+	if m.activeTab != 0 || m.confirmActive {
+		m.scriptsStale = true
+		return
+	}
+	m.scriptsStale = false
+	m.refreshKeepingSelection()
+}
+
+// refreshKeepingSelection refreshes the list in place, keeping the selected
+// item and the preview's scroll position if the item is still listed.
+func (m *Model) refreshKeepingSelection() {
+	var selected string
+	if sel, ok := m.list.SelectedItem().(scripts.Item); ok {
+		selected = sel.Description()
+	}
+	offset := m.vp.YOffset
+
+	m.refreshView()
+	for i, item := range m.list.Items() {
+		if scriptItem, ok := item.(scripts.Item); ok && scriptItem.Description() == selected {
+			m.list.Select(i)
+			m.updatePreview()
+			m.vp.SetYOffset(offset)
+			break
+		}
+	}
+}
+
+// togglePin pins the selected script, or unpins it if it is pinned. Pins
+// are saved by path in the repository, so they outlive re-clones.
+func (m *Model) togglePin() {
+	sel, ok := m.list.SelectedItem().(scripts.Item)
+	if !ok || !sel.IsScript() {
+		return
+	}
+	rel, ok := scripts.RelativePath(m.config.ScriptbinPath, sel.Description())
+	if !ok {
+		return
+	}
+
+	favorites := slices.Clone(m.config.Favorites)
+	if i := slices.Index(favorites, rel); i >= 0 {
+		favorites = slices.Delete(favorites, i, i+1)
+	} else {
+		favorites = append(favorites, rel)
+	}
+	if err := config.SaveFavorites(m.config.RepoURL, favorites); err != nil {
+		m.vp.SetContent("❌ Could not save favorites: " + err.Error())
+		return
+	}
+	m.config.Favorites = favorites
+	m.updatePins()
+	m.refreshKeepingSelection()
+}
+
+// updatePins marks the pinned scripts of the current repository in the list.
+func (m *Model) updatePins() {
+	paths := make([]string, len(m.config.Favorites))
+	for i, rel := range m.config.Favorites {
+		paths[i] = filepath.Join(m.config.ScriptbinPath, filepath.FromSlash(rel))
+	}
+	m.scriptDelegate.SetPinned(paths)
+}
+
+// reloadScripts reloads the script items from the repository root.
+func (m *Model) reloadScripts() {
 	// Reload script items from the new repository location
 	m.currentPath = m.config.ScriptbinPath
-	newItems := scripts.GetItems(m.config.ScriptbinPath)
+	newItems := loadItems(m.config, m.config.ScriptbinPath)
 	m.scriptItems = newItems
 	m.allScriptItems = newItems
+	m.scopeItems = nil
 	
 	// Clear parent paths since we're starting fresh
 	m.parentPaths = []ParentNav{}
+	m.reloadWorkflows()
+	m.updatePins()
 	
 	// If we're currently on the scripts tab, update the list immediately
 	if m.activeTab == 0 {
@@ -438,7 +854,7 @@ func (m *Model) refreshRepository() error {
 		// Update preview if there are scripts
 		if len(m.scriptItems) > 0 {
 			if s, ok := m.scriptItems[0].(scripts.Item); ok && s.IsScript() {
-				content := scripts.ReadContentWithHighlighting(s.Description(), m.cache)
+				content := previewContent(s, m.cache, m.theme)
 				m.vp.SetContent(content)
 			} else {
 				m.vp.SetContent("Select a script to preview...")
@@ -447,6 +863,4 @@ func (m *Model) refreshRepository() error {
 			m.vp.SetContent("No scripts found in repository.")
 		}
 	}
-	
-	return nil
 }
@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,8 +16,19 @@ import (
 	"github.com/rocketpowerinc/go-pwr/internal/ui/components"
 )
 
-// Update handles UI state updates.
+// Update handles UI state updates, then starts the content search a
+// keystroke or refresh asked for.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if next, ok := model.(Model); ok && next.contentSearchDue {
+		next.contentSearchDue = false
+		return next, tea.Batch(cmd, next.scheduleContentSearch())
+	}
+	return model, cmd
+}
+
+// update handles a message.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
@@ -23,6 +37,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.setSizes()
 		return m, nil
 
+	case repositorySyncedMsg:
+		m.finishRepositorySync(msg)
+		if msg.err != nil {
+			return m, nil
+		}
+		cmd := m.watchScripts()
+		return m, cmd
+
+	case contentSearchDueMsg:
+		if msg.seq != m.searchSeq {
+			return m, nil // Typing went on
+		}
+		cmd := m.startContentSearch()
+		return m, cmd
+
+	case contentResultsMsg:
+		m.finishContentSearch(msg)
+		return m, nil
+
+	case requirementsCheckedMsg:
+		m.finishRequirementsCheck(msg)
+		return m, nil
+
+	case workflowPlannedMsg:
+		cmd := m.finishWorkflowPlan(msg)
+		return m, cmd
+
+	case workflowStepDoneMsg:
+		cmd := m.finishWorkflowStep(msg)
+		return m, cmd
+
+	case requirementsProbedMsg:
+		if msg.unmet {
+			m.reloadChangedScripts(nil) // Mark the scripts with missing requirements
+		}
+		return m, waitForRequirementProbes()
+
+	case scriptsChangedMsg:
+		if msg.watcher != m.watcher {
+			return m, nil // From a directory no longer shown
+		}
+		m.reloadChangedScripts(msg.paths)
+		return m, waitForScriptChanges(m.watcher)
+
 	case tea.MouseMsg:
 		if msg.Type == tea.MouseLeft && msg.Y == 0 {
 			// Handle tab clicks
@@ -46,16 +104,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		// Handle escape key with multiple detection methods
 		if msg.String() == "escape" || msg.Type == tea.KeyEscape {
-			if m.repositoryInputActive && m.activeTab == 1 {
+			if m.confirmActive && m.activeTab == 0 {
+				m.closeConfirm()
+				m.updatePreview()
+				return m, nil
+			} else if m.paramFormActive && m.activeTab == 0 {
+				m.closeParamForm()
+				m.updatePreview()
+				return m, nil
+			} else if m.workflowConfirm && m.activeTab == 1 {
+				m.cancelWorkflow()
+				return m, nil
+			} else if m.repositoryInputActive && m.activeTab == 2 {
 				m.repositoryInputActive = false
 				m.repositoryInput.SetActive(false)
 				m.focus = FocusPreview
 				return m, nil
-			} else if m.repositoryViewActive && m.activeTab == 1 {
+			} else if m.repositoryViewActive && m.activeTab == 2 {
 				m.repositoryViewActive = false
 				m.focus = FocusPreview
 				return m, nil
-			} else if m.repositoryResetActive && m.activeTab == 1 {
+			} else if m.repositoryResetActive && m.activeTab == 2 {
 				m.repositoryResetActive = false
 				m.focus = FocusPreview
 				return m, nil
@@ -73,6 +142,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
+		// Handle the administrator rights confirmation if it is shown
+		if m.confirmActive && m.activeTab == 0 && msg.String() != "ctrl+c" {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirmLaunch()
+			case "n", "N":
+				m.closeConfirm()
+				m.updatePreview()
+			}
+			return m, nil
+		}
+
+		// Handle the workflow confirmation if it is shown
+		if m.workflowConfirm && m.activeTab == 1 && msg.String() != "ctrl+c" {
+			switch msg.String() {
+			case "y", "Y":
+				m.workflowConfirm = false
+				cmd := m.runWorkflowStep(0)
+				return m, cmd
+			case "n", "N":
+				m.cancelWorkflow()
+			}
+			return m, nil
+		}
+
+		// Handle the parameter form if it is open
+		if m.paramFormActive && m.activeTab == 0 && msg.String() != "ctrl+c" {
+			submitted, cmd := m.paramForm.Update(msg)
+			if submitted {
+				m.submitParamForm()
+			}
+			return m, cmd
+		}
+
 		// Handle search input if search is active
 		if m.searchActive && m.activeTab == 0 {
 			switch msg.String() {
@@ -81,6 +184,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchInput.SetActive(false)
 				m.focus = FocusList
 				return m, nil
+			case "tab":
+				// Cycle through tag, content and fuzzy name search
+				m.searchInput.NextMode()
+				m.applySearch()
+				return m, nil
 			default:
 				// Pass all other keys (except escape, handled above) to search input
 				cmd = m.searchInput.Update(msg)
@@ -91,7 +199,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Handle repository input if repository input is active
-		if m.repositoryInputActive && m.activeTab == 1 {
+		if m.repositoryInputActive && m.activeTab == 2 {
 			switch msg.String() {
 			case "enter":
 				// Validate and save the repository URL
@@ -107,23 +215,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					} else {
 						// Update the config immediately
 						m.config.RepoURL = url
+						m.config.RepoSHA256 = ""
+						m.config.RepoMirrors = nil
 						m.repositoryInputActive = false
 						m.repositoryInput.SetActive(false)
 						m.repositoryResetActive = true // Show result in dedicated screen
 						m.repositoryViewActive = false
 						m.focus = FocusPreview
 						
-						// Try to refresh repository immediately
-						if err := m.refreshRepository(); err != nil {
-							m.vp.SetContent("✅ Repository saved, but failed to load scripts: " + err.Error() + "\n\nNew repository: " + url + "\n\nPlease restart the application to see the changes.")
-						} else {
-							m.vp.SetContent(fmt.Sprintf("✅ Custom Repository Successfully Set!\n\n🔄 New Repository URL:\n%s\n\n📁 Scripts Location:\n%s\n\n✨ Scripts have been refreshed and are ready to use.\nSwitch to the Scripts tab to see your custom content.", 
-								url, m.config.ScriptbinPath))
-						}
-						
 						// Update the repository items to show the new current repo
 						m.updateRepositoryItems()
-						return m, nil
+
+						// Refresh the repository in the background
+						m.vp.SetContent("⏳ Repository saved, loading scripts from:\n" + url + "\n\nPress Ctrl+C to cancel.")
+						cmd := m.startRepositorySync("set_repo")
+						return m, cmd
 					}
 				}
 				return m, nil
@@ -149,6 +255,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.refreshView()
 				return m, nil
 			}
+		case "ctrl+t":
+			if m.activeTab == 0 {
+				// Toggle hiding scripts that cannot run on this host
+				m.config.CompatibleOnly = !m.config.CompatibleOnly
+				config.SaveCompatibleOnly(m.config.CompatibleOnly) // Best effort; the toggle still applies
+				m.refreshView()
+				return m, nil
+			}
+		case "p":
+			if m.activeTab == 0 && m.focus == FocusList {
+				// Pin or unpin the selected script
+				m.togglePin()
+				return m, nil
+			}
 		case "tab":
 			m.switchTab((m.activeTab + 1) % len(m.tabs))
 		case "shift+tab":
@@ -157,11 +277,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Tab switching - same as regular tab but with ctrl modifier
 			m.switchTab((m.activeTab + 1) % len(m.tabs))
 		case "ctrl+left", "cmd+left", "alt+left", "shift+left", "ctrl+h":
-			if m.activeTab == 0 || m.activeTab == 1 {
+			if m.activeTab <= 2 {
 				m.focus = FocusList
 			}
 		case "ctrl+right", "cmd+right", "alt+right", "shift+right", "ctrl+l":
-			if m.activeTab == 0 || m.activeTab == 1 {
+			if m.activeTab <= 2 {
 				m.focus = FocusPreview
 			}
 		case "left":
@@ -183,18 +303,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			return m.handleEnter()
 		case "page_up":
-			if m.focus == FocusPreview && m.activeTab == 0 {
+			if m.focus == FocusPreview && m.activeTab <= 1 {
 				for i := 0; i < 10; i++ {
 					m.vp.LineUp(1)
 				}
 			}
 		case "page_down":
-			if m.focus == FocusPreview && m.activeTab == 0 {
+			if m.focus == FocusPreview && m.activeTab <= 1 {
 				for i := 0; i < 10; i++ {
 					m.vp.LineDown(1)
 				}
 			}
 		case "q", "ctrl+c":
+			// Ctrl+C cancels a running sync before it quits the application
+			if msg.String() == "ctrl+c" && m.syncCancel != nil {
+				m.syncCancel()
+				m.syncCancel = nil
+				m.vp.SetContent("⏹ Cancelling repository sync...")
+				return m, nil
+			}
 			return m, tea.Quit
 		}
 	}
@@ -219,15 +346,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) refreshView() {
 	if m.recursiveMode {
 		// Get all scripts recursively
-		allItems := scripts.GetAllScriptsRecursively(m.config.ScriptbinPath)
+		allItems := visibleItems(m.config, scripts.GetAllScriptsRecursively(m.config.ScriptbinPath))
 		m.allScriptItems = allItems
 		m.scriptItems = allItems
 	} else {
 		// Get items from current directory only
-		items := scripts.GetItems(m.currentPath)
+		items := loadItems(m.config, m.currentPath)
 		m.allScriptItems = items
 		m.scriptItems = items
 	}
+	m.scopeItems = nil // Files may have changed
 	
 	// Apply current search if any
 	m.applySearch()
@@ -236,13 +364,28 @@ func (m *Model) refreshView() {
 // applySearch filters the script items based on the search input
 func (m *Model) applySearch() {
 	searchTerm := strings.TrimSpace(m.searchInput.Value())
+	m.searchSeq++
+	m.contentSearchDue = false
 	
 	if searchTerm == "" {
 		// Show all items when search is empty
 		m.scriptItems = m.allScriptItems
+	} else if m.searchInput.Mode() == components.SearchContent {
+		// The current results stay until contentResultsMsg replaces them
+		m.searchInput.SetError("")
+		m.contentSearchDue = true
+		return
+	} else if m.searchInput.Mode() == components.SearchFuzzy {
+		m.searchInput.SetError("")
+		m.scriptItems = scripts.FilterItemsByFuzzy(m.searchScope(), searchTerm)
 	} else {
-		// Split search term into individual tags
-		searchTags := strings.Fields(strings.ToLower(searchTerm))
+		query, err := scripts.ParseQuery(searchTerm)
+		if err != nil {
+			// Keep the current results until the query is complete again
+			m.searchInput.SetError(err.Error())
+			return
+		}
+		m.searchInput.SetError("")
 		if m.recursiveMode {
 			// In recursive mode, only show scripts (no directories)
 			var scriptOnlyItems []list.Item
@@ -251,12 +394,16 @@ func (m *Model) applySearch() {
 					scriptOnlyItems = append(scriptOnlyItems, item)
 				}
 			}
-			m.scriptItems = scripts.FilterItemsByTags(scriptOnlyItems, searchTags)
+			m.scriptItems = scripts.FilterItemsByQuery(scriptOnlyItems, query)
 		} else {
-			m.scriptItems = scripts.FilterItemsByTags(m.allScriptItems, searchTags)
+			m.scriptItems = scripts.FilterItemsByQuery(m.allScriptItems, query)
 		}
 	}
-	
+	m.showSearchResults()
+}
+
+// showSearchResults lists the filtered scripts and previews the first.
+func (m *Model) showSearchResults() {
 	m.list.SetItems(m.scriptItems)
 	if len(m.scriptItems) > 0 {
 		m.list.Select(0)
@@ -270,6 +417,83 @@ func (m *Model) applySearch() {
 	}
 }
 
+// searchScope returns the scripts content and fuzzy search look through:
+// every script below the current directory, even in directory mode. The
+// directory's scripts are listed once and kept until the list is reloaded.
+func (m *Model) searchScope() []list.Item {
+	if scope, ok := m.cachedScope(); ok {
+		return scope
+	}
+	m.scopePath, m.scopeItems = m.currentPath, visibleItems(m.config, scripts.GetAllScriptsRecursively(m.currentPath))
+	return m.scopeItems
+}
+
+// cachedScope returns the search scope if it needs no listing.
+func (m *Model) cachedScope() ([]list.Item, bool) {
+	if m.recursiveMode || m.currentPath == scripts.FavoritesPath(m.config.ScriptbinPath) {
+		return m.allScriptItems, true
+	}
+	if m.scopeItems != nil && m.scopePath == m.currentPath {
+		return m.scopeItems, true
+	}
+	return nil, false
+}
+
+// searchDelay is how long content search waits for typing to pause.
+const searchDelay = 150 * time.Millisecond
+
+// contentSearchDueMsg fires once typing paused for searchDelay.
+type contentSearchDueMsg struct {
+	seq int
+}
+
+// contentResultsMsg carries the scripts a background content search found.
+type contentResultsMsg struct {
+	seq   int
+	term  string
+	path  string      // Directory searched
+	scope []list.Item // Every script below path, to keep for later searches
+	items []list.Item
+}
+
+// scheduleContentSearch waits for typing to pause before searching.
+func (m *Model) scheduleContentSearch() tea.Cmd {
+	seq := m.searchSeq
+	return tea.Tick(searchDelay, func(time.Time) tea.Msg {
+		return contentSearchDueMsg{seq: seq}
+	})
+}
+
+// startContentSearch reads the scripts in scope off the UI goroutine,
+// listing the scope first if it is not cached.
+func (m *Model) startContentSearch() tea.Cmd {
+	seq, path, term, cfg := m.searchSeq, m.currentPath, strings.TrimSpace(m.searchInput.Value()), m.config
+	scope, cached := m.cachedScope()
+	return func() tea.Msg {
+		if !cached {
+			scope = visibleItems(cfg, scripts.GetAllScriptsRecursively(path))
+		}
+		return contentResultsMsg{seq: seq, term: term, path: path, scope: scope, items: scripts.FilterItemsByContent(scope, term)}
+	}
+}
+
+// finishContentSearch shows the results of the latest content search.
+func (m *Model) finishContentSearch(msg contentResultsMsg) {
+	if msg.seq != m.searchSeq || msg.path != m.currentPath {
+		return
+	}
+	if _, cached := m.cachedScope(); !cached {
+		m.scopePath, m.scopeItems = msg.path, msg.scope
+	}
+	if m.searchInput.Mode() != components.SearchContent || strings.TrimSpace(m.searchInput.Value()) != msg.term {
+		return // The search was cleared or changed mode meanwhile
+	}
+	m.scriptItems = msg.items
+	if m.activeTab == 0 {
+		m.showSearchResults()
+	}
+}
+
 // handleUpDown handles up/down key navigation.
 func (m Model) handleUpDown(msg tea.KeyMsg, isUp bool) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -282,16 +506,22 @@ func (m Model) handleUpDown(msg tea.KeyMsg, isUp bool) (tea.Model, tea.Cmd) {
 			m.updatePreview()
 		}
 	} else if m.focus == FocusList && m.activeTab == 1 {
+		prevIndex := m.list.Index()
+		m.list, cmd = m.list.Update(msg)
+		if m.list.Index() != prevIndex {
+			m.updateWorkflowPreview()
+		}
+	} else if m.focus == FocusList && m.activeTab == 2 {
 		m.list, cmd = m.list.Update(msg)
-	} else if m.focus == FocusPreview && m.activeTab == 0 {
+	} else if m.focus == FocusPreview && m.activeTab <= 1 {
 		if isUp {
 			m.vp.LineUp(1)
 		} else {
 			m.vp.LineDown(1)
 		}
-	} else if m.focus == FocusPreview && m.activeTab == 1 && !m.repositoryInputActive {
+	} else if m.focus == FocusPreview && m.activeTab == 2 && !m.repositoryInputActive {
 		m.optionsRightList, cmd = m.optionsRightList.Update(msg)
-	} else if m.focus == FocusRepositoryInput && m.activeTab == 1 {
+	} else if m.focus == FocusRepositoryInput && m.activeTab == 2 {
 		// Repository input handles its own navigation
 		cmd = m.repositoryInput.Update(msg)
 	}
@@ -307,10 +537,17 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 			if sel.IsDirectory() && !m.recursiveMode {
 				m.navigateIntoDirectory(sel)
 			} else if sel.IsScript() {
-				m.executeScript(sel)
+				cmd := m.executeScript(sel)
+				return m, cmd
 			}
 		}
 	} else if m.activeTab == 1 && m.focus == FocusList {
+		// Workflows tab - run the selected workflow
+		if workflow, ok := m.selectedWorkflow(); ok {
+			cmd := m.startWorkflow(workflow)
+			return m, cmd
+		}
+	} else if m.activeTab == 2 && m.focus == FocusList {
 		// Options tab - select category
 		if sel, ok := m.list.SelectedItem().(components.CategoryItem); ok {
 			m.selectedCategory = sel.Category
@@ -326,7 +563,7 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 				m.focus = FocusPreview
 			}
 		}
-	} else if m.activeTab == 1 && m.focus == FocusPreview {
+	} else if m.activeTab == 2 && m.focus == FocusPreview {
 		// Options tab - apply selected option
 		if m.selectedCategory == "color_schemes" {
 			if sel, ok := m.optionsRightList.SelectedItem().(components.OptionItem); ok {
@@ -334,7 +571,8 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 			}
 		} else if m.selectedCategory == "repository" {
 			if sel, ok := m.optionsRightList.SelectedItem().(components.OptionItem); ok {
-				m.handleRepositoryAction(sel.Action)
+				cmd := m.handleRepositoryAction(sel.Action)
+				return m, cmd
 			}
 		}
 	}
@@ -372,18 +610,26 @@ func (m Model) View() string {
 	case 0:
 		body = m.renderScriptsTab()
 	case 1:
-		body = m.renderOptionsTab()
+		body = m.renderWorkflowsTab()
 	case 2:
+		body = m.renderOptionsTab()
+	case 3:
 		body = m.renderAboutTab()
 	}
 
 	// Render footer - responsive based on terminal width
 	var footerText string
-	if m.activeTab == 0 && m.searchActive {
-		footerText = "'Enter' Apply • 'Esc' Cancel • Type to search..."
-	} else if m.activeTab == 1 && m.repositoryInputActive {
+	if m.activeTab == 0 && m.confirmActive {
+		footerText = "'y' Run as Administrator • 'n'/'Esc' Cancel"
+	} else if m.activeTab == 1 && m.workflowConfirm {
+		footerText = "'y' Run Workflow • 'n'/'Esc' Cancel"
+	} else if m.activeTab == 0 && m.paramFormActive {
+		footerText = "'Enter' Next/Run • 'Tab' Next Field • '←→' Cycle Choices • 'Esc' Cancel"
+	} else if m.activeTab == 0 && m.searchActive {
+		footerText = "'Enter' Apply • 'Esc' Cancel • 'Tab' Tags/Contents/Fuzzy • Type to search..."
+	} else if m.activeTab == 2 && m.repositoryInputActive {
 		footerText = "'Enter' Save Repository • 'Esc' Cancel • Type repository URL"
-	} else if m.activeTab == 1 && (m.repositoryViewActive || m.repositoryResetActive) {
+	} else if m.activeTab == 2 && (m.repositoryViewActive || m.repositoryResetActive) {
 		footerText = "'Esc' Back to Repository Options • 'Tab' Switch Tabs • 'q' Quit"
 	} else if m.activeTab == 0 {
 		if m.width < 80 {
@@ -391,10 +637,10 @@ func (m Model) View() string {
 			footerText = "'Tab' Tabs • '↑↓' Navigate • 'Enter' Run • 'Ctrl+F' Search • 'q' Quit"
 		} else if m.width < 120 {
 			// Medium footer for medium terminals
-			footerText = "'Tab' Switch • '←↑↓→' Navigate • 'Enter' Run/Select • 'Ctrl+F' Search • 'Ctrl+R' Recursive • 'Ctrl+H/L' Switch Panes • 'q' Quit"
+			footerText = "'Tab' Switch • '←↑↓→' Navigate • 'Enter' Run/Select • 'p' Pin • 'Ctrl+F' Search • 'Ctrl+R' Recursive • 'Ctrl+T' Compatible • 'Ctrl+H/L' Switch Panes • 'q' Quit"
 		} else {
 			// Full footer for large terminals
-			footerText = "'Tab' Switch Tabs • '←↑↓→' Navigate • 'Ctrl+H/L' Switch Panes • 'Enter' Run/Select • 'Ctrl+F' Search • 'Ctrl+R' Toggle Recursive • 'q' Quit"
+			footerText = "'Tab' Switch Tabs • '←↑↓→' Navigate • 'Ctrl+H/L' Switch Panes • 'Enter' Run/Select • 'p' Pin • 'Ctrl+F' Search • 'Ctrl+R' Toggle Recursive • 'Ctrl+T' Compatible Only • 'q' Quit"
 		}
 	} else {
 		if m.width < 80 {
@@ -459,6 +705,23 @@ func (m Model) renderScriptsTab() string {
 		Width(maxBreadcrumbWidth).
 		Render(breadcrumbText)
 
+	// Let the user know when scripts came from a mirror instead of the primary
+	if m.usingMirror() {
+		mirrorText := "🔁 Mirror: " + m.config.ActiveRepoURL
+		if len(mirrorText) > maxBreadcrumbWidth {
+			mirrorText = mirrorText[:maxBreadcrumbWidth-3] + "..."
+		}
+		breadcrumb += "\n" + lipgloss.NewStyle().
+			Foreground(m.theme.Current.Accent).
+			Render(mirrorText)
+	}
+
+	if m.config.CompatibleOnly {
+		breadcrumb += "\n" + lipgloss.NewStyle().
+			Foreground(m.theme.Current.Accent).
+			Render("✔ Compatible scripts only")
+	}
+
 	// Search input - simplified approach
 	var searchSection string
 	availableSearchWidth := leftPanelWidth - 8 // Account for padding and prefix
@@ -480,6 +743,14 @@ func (m Model) renderScriptsTab() string {
 	var rightContent string
 	if rightPanelWidth > 0 {
 		rightContent = m.vp.View()
+		if m.paramFormActive {
+			m.paramForm.SetWidth(rightPanelWidth - 8)
+			rightContent = m.paramForm.View()
+		}
+	} else if m.paramFormActive {
+		// No preview pane on small terminals, so the form replaces the list
+		m.paramForm.SetWidth(leftPanelWidth - 8)
+		leftContent = m.paramForm.View()
 	}
 
 	// Focus highlighting
@@ -617,7 +888,7 @@ func (m Model) renderAboutTab() string {
 }
 
 // handleRepositoryAction handles repository-related actions
-func (m *Model) handleRepositoryAction(action string) {
+func (m *Model) handleRepositoryAction(action string) tea.Cmd {
 	switch action {
 	case "set_repo":
 		// Activate repository input
@@ -631,7 +902,7 @@ func (m *Model) handleRepositoryAction(action string) {
 			m.repositoryInput.SetValue(m.config.RepoURL)
 		}
 		m.focus = FocusRepositoryInput
-		m.vp.SetContent("Enter a Git repository URL ending with .git\n\nSupported formats:\n- https://github.com/username/repo.git\n- https://gitlab.com/username/repo.git\n- git@github.com:username/repo.git\n\nPress Enter to save, Esc to cancel")
+		m.vp.SetContent("Enter a Git repository URL ending with .git\n\nSupported formats:\n- https://github.com/username/repo.git\n- https://gitlab.com/username/repo.git\n- git@github.com:username/repo.git\n- https://example.com/scriptbin.tar.gz (or .zip)\n\nPress Enter to save, Esc to cancel")
 	case "reset_repo":
 		// Activate repository reset view
 		m.repositoryResetActive = true
@@ -646,19 +917,16 @@ func (m *Model) handleRepositoryAction(action string) {
 			m.vp.SetContent("❌ Failed to reset repository: " + err.Error())
 		} else {
 			// Update the config immediately
-			defaultRepo := config.GetDefaultRepoURL()
-			m.config.RepoURL = defaultRepo
-			
-			// Try to refresh repository immediately
-			if err := m.refreshRepository(); err != nil {
-				m.vp.SetContent("✅ Repository reset to default, but failed to refresh scripts: " + err.Error() + "\n\nPlease restart the application to see the changes.")
-			} else {
-				m.vp.SetContent(fmt.Sprintf("✅ Repository Successfully Reset!\n\n🔄 Reset to Default Repository:\n%s\n\n📁 Scripts Location:\n%s\n\n✨ Scripts have been refreshed and are ready to use.\nSwitch to the Scripts tab to see the default content.", 
-					defaultRepo, m.config.ScriptbinPath))
-			}
+			m.config.RepoURL = config.GetDefaultRepoURL()
+			m.config.RepoSHA256 = ""
+			m.config.RepoMirrors = nil
 			
 			// Update the repository items to show the new current repo
 			m.updateRepositoryItems()
+
+			// Refresh the repository in the background
+			m.vp.SetContent("⏳ Repository reset, loading the default scripts...\n\nPress Ctrl+C to cancel.")
+			return m.startRepositorySync("reset_repo")
 		}
 	case "view_repo":
 		// Activate repository view
@@ -683,6 +951,12 @@ func (m *Model) handleRepositoryAction(action string) {
 		
 		detailsSection = fmt.Sprintf("🌐 Current Repository URL:\n%s\n\n🏠 Default Repository URL:\n%s", 
 			m.config.RepoURL, defaultRepo)
+		if len(m.config.RepoMirrors) > 0 {
+			detailsSection += "\n\n🪞 Mirrors:\n" + strings.Join(m.config.RepoMirrors, "\n")
+		}
+		if m.usingMirror() {
+			detailsSection += "\n\n🔁 Synced From Mirror:\n" + m.config.ActiveRepoURL
+		}
 		
 		pathSection = fmt.Sprintf("📁 Local Scripts Path:\n%s\n\n💡 This is where go-pwr loads scripts from.", 
 			m.config.ScriptbinPath)
@@ -698,6 +972,42 @@ func (m *Model) handleRepositoryAction(action string) {
 		m.vp.SetContent(fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s", 
 			headerSection, statusSection, detailsSection, pathSection, repoTypeInfo))
 	}
+	return nil
+}
+
+// finishRepositorySync applies the result of a background repository sync.
+func (m *Model) finishRepositorySync(msg repositorySyncedMsg) {
+	m.syncCancel = nil
+
+	// Ignore results for a repository that has since been replaced
+	if msg.cfg.RepoURL != m.config.RepoURL {
+		return
+	}
+
+	if msg.err != nil {
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+			m.vp.SetContent("⏹ Repository sync cancelled.\n\nThe repository setting is saved; scripts will be loaded the next time go-pwr starts.")
+		case msg.action == "reset_repo":
+			m.vp.SetContent("✅ Repository reset to default, but failed to refresh scripts: " + msg.err.Error() + "\n\nPlease restart the application to see the changes.")
+		default:
+			m.vp.SetContent("✅ Repository saved, but failed to load scripts: " + msg.err.Error() + "\n\nNew repository: " + m.config.RepoURL + "\n\nPlease restart the application to see the changes.")
+		}
+		return
+	}
+
+	m.config.ScriptbinPath = msg.cfg.ScriptbinPath
+	m.config.ActiveRepoURL = msg.cfg.ActiveRepoURL
+	m.config.Favorites = config.LoadFavorites(m.config.RepoURL) // Pins belong to the repository they were made in
+	m.reloadScripts()
+
+	if msg.action == "reset_repo" {
+		m.vp.SetContent(fmt.Sprintf("✅ Repository Successfully Reset!\n\n🔄 Reset to Default Repository:\n%s\n\n📁 Scripts Location:\n%s\n\n✨ Scripts have been refreshed and are ready to use.\nSwitch to the Scripts tab to see the default content.", 
+			m.config.RepoURL, m.config.ScriptbinPath) + m.mirrorNotice())
+	} else {
+		m.vp.SetContent(fmt.Sprintf("✅ Custom Repository Successfully Set!\n\n🔄 New Repository URL:\n%s\n\n📁 Scripts Location:\n%s\n\n✨ Scripts have been refreshed and are ready to use.\nSwitch to the Scripts tab to see your custom content.", 
+			m.config.RepoURL, m.config.ScriptbinPath) + m.mirrorNotice())
+	}
 }
 
 // updateRepositoryItems updates the repository items list with current config
@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/rocketpowerinc/go-pwr/internal/scripts"
+	"github.com/rocketpowerinc/go-pwr/internal/ui/components"
+	"github.com/rocketpowerinc/go-pwr/pkg/platform"
+)
+
+// workflowStepDoneMsg reports that a workflow step exited.
+type workflowStepDoneMsg struct {
+	run  *scripts.WorkflowRun
+	step int
+	err  error
+}
+
+// reloadWorkflows reads the workflows again, e.g. after the repository or
+// its root manifest changed.
+func (m *Model) reloadWorkflows() {
+	m.workflows, _ = scripts.LoadWorkflows(m.config.ScriptbinPath, m.config.Workflows)
+	m.workflowItems = components.WorkflowItems(m.workflows)
+	if m.activeTab == 1 {
+		index := m.list.Index()
+		m.list.SetItems(m.workflowItems)
+		m.list.Select(min(index, max(len(m.workflowItems)-1, 0)))
+		m.updateWorkflowPreview()
+	}
+}
+
+// selectedWorkflow returns the workflow selected in the Workflows tab.
+func (m *Model) selectedWorkflow() (scripts.Workflow, bool) {
+	sel, ok := m.list.SelectedItem().(components.OptionItem)
+	if !ok {
+		return scripts.Workflow{}, false
+	}
+	return scripts.FindWorkflow(m.workflows, sel.Name)
+}
+
+// updateWorkflowPreview shows the selected workflow, with the status of its
+// steps if it is the one that ran last.
+func (m *Model) updateWorkflowPreview() {
+	if m.activeTab != 1 {
+		return
+	}
+	workflow, ok := m.selectedWorkflow()
+	if !ok {
+		m.vp.SetContent("No workflows defined.\n\nAdd a workflows: section to the repository's scriptbin.yaml, or \"workflows\" to your config, to run several scripts in a row.")
+		return
+	}
+	m.vp.SetContent(components.WorkflowStatus(workflow, m.workflowRun, m.theme))
+}
+
+// workflowPlannedMsg reports a workflow planned and its steps' requirements
+// checked, ready to run.
+type workflowPlannedMsg struct {
+	workflow scripts.Workflow
+	steps    []scripts.PlannedStep
+	unmet    [][]platform.Unmet // Missing requirements of each step
+	err      error
+}
+
+// startWorkflow plans a workflow and checks every step before running the
+// first. Planning lists the repository and checking may probe versions, so
+// both run off the UI goroutine; finishWorkflowPlan takes over.
+func (m *Model) startWorkflow(workflow scripts.Workflow) tea.Cmd {
+	if (m.workflowRun != nil && m.workflowRun.Running()) || m.workflowPlan != "" {
+		return nil // One workflow at a time
+	}
+
+	m.workflowPlan = workflow.Name
+	m.vp.SetContent(fmt.Sprintf("Checking the steps of %s...", workflow.Name))
+	root := m.config.ScriptbinPath
+	return func() tea.Msg {
+		steps, err := workflow.Plan(root)
+		if err != nil {
+			return workflowPlannedMsg{workflow: workflow, err: err}
+		}
+		// Check requirements afresh; something may have been installed
+		// since. Steps sharing a requirement probe it once.
+		for _, step := range steps {
+			platform.ForgetRequirementChecks(step.Item.Requires())
+		}
+		unmet := make([][]platform.Unmet, len(steps))
+		for i, step := range steps {
+			unmet[i] = platform.CheckRequirements(step.Item.Requires())
+		}
+		return workflowPlannedMsg{workflow: workflow, steps: steps, unmet: unmet}
+	}
+}
+
+// finishWorkflowPlan runs a planned workflow, asking for confirmation if any
+// step needs administrator rights.
+func (m *Model) finishWorkflowPlan(msg workflowPlannedMsg) tea.Cmd {
+	if msg.workflow.Name != m.workflowPlan {
+		return nil
+	}
+	m.workflowPlan = ""
+	if m.activeTab != 1 {
+		return nil // The user moved on meanwhile
+	}
+
+	workflow, steps := msg.workflow, msg.steps
+	if msg.err != nil {
+		m.vp.SetContent(fmt.Sprintf("❌ Cannot run %s:\n\n%v", workflow.Name, msg.err))
+		return nil
+	}
+
+	var problems []string
+	needsAdmin := false
+	for i, step := range steps {
+		if interp, ok := platform.ResolveInterpreter(step.Item.Description()); ok && !interp.Available() {
+			problems = append(problems, fmt.Sprintf("Step %d: %s is not installed.", i+1, interp.Program()))
+		}
+		if unmet := msg.unmet[i]; len(unmet) > 0 {
+			problems = append(problems, fmt.Sprintf("Step %d (%s):\n%s", i+1, step.Item.Title(), components.MissingRequirements(unmet, m.theme)))
+		}
+		if err := m.checkPrivilege(step.Item); err != nil {
+			problems = append(problems, fmt.Sprintf("Step %d: %v", i+1, err))
+		}
+		if step.Item.NeedsAdmin() && !platform.IsElevated() {
+			needsAdmin = true
+			steps[i].Options.Elevate, _ = platform.ElevationCommand(m.config.ElevateCommand) // checkPrivilege reported failures
+		}
+	}
+	if len(problems) > 0 {
+		m.vp.SetContent(fmt.Sprintf("❌ Cannot run %s yet.\n\n%s\n\nFix the steps above, then press Enter again.", workflow.Name, strings.Join(problems, "\n\n")))
+		return nil
+	}
+
+	m.workflowRun = scripts.NewWorkflowRun(workflow, steps)
+	if needsAdmin {
+		m.workflowConfirm = true
+		m.vp.SetContent(components.WorkflowStatus(workflow, m.workflowRun, m.theme) +
+			"\n\n🔒 Some steps require administrator rights and can change your system.\n\nPress 'y' to run the workflow, 'n' or 'Esc' to cancel.")
+		return nil
+	}
+	return m.runWorkflowStep(0)
+}
+
+// cancelWorkflow drops a workflow that is waiting for confirmation.
+func (m *Model) cancelWorkflow() {
+	m.workflowConfirm = false
+	m.workflowRun = nil
+	m.updateWorkflowPreview()
+}
+
+// runWorkflowStep runs step i of the current workflow in the terminal,
+// suspending the UI until it exits.
+func (m *Model) runWorkflowStep(i int) tea.Cmd {
+	run := m.workflowRun
+	for i >= 0 {
+		step := run.Steps[i]
+		cmd, err := platform.ScriptCommand(step.Item.Description(), step.Options)
+		if err != nil {
+			i = run.Finish(i, err)
+			continue
+		}
+		run.Start(i)
+		m.updateWorkflowPreview()
+		index := i
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return workflowStepDoneMsg{run: run, step: index, err: err}
+		})
+	}
+	m.updateWorkflowPreview()
+	return nil
+}
+
+// finishWorkflowStep records the result of a step and starts the next one.
+func (m *Model) finishWorkflowStep(msg workflowStepDoneMsg) tea.Cmd {
+	if msg.run != m.workflowRun {
+		return nil
+	}
+	return m.runWorkflowStep(msg.run.Finish(msg.step, msg.err))
+}
+
+// renderWorkflowsTab renders the workflows tab.
+func (m Model) renderWorkflowsTab() string {
+	leftPanelWidth := (m.width / 3) - 2
+	rightPanelWidth := ((m.width * 2) / 3) - 2
+	panelHeight := m.height - 10
+
+	leftContent := m.list.View()
+	if len(m.workflowItems) == 0 {
+		leftContent = lipgloss.NewStyle().Faint(true).Render("No workflows")
+	}
+
+	leftBorderColor := m.theme.Current.Accent
+	rightBorderColor := lipgloss.Color("244")
+	if m.focus == FocusPreview {
+		leftBorderColor = lipgloss.Color("244")
+		rightBorderColor = m.theme.Current.Accent
+	}
+
+	leftPanel := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(leftBorderColor).
+		Width(leftPanelWidth).
+		Height(panelHeight).
+		Padding(1, 2).
+		Render(leftContent)
+
+	rightPanel := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(rightBorderColor).
+		Width(rightPanelWidth).
+		Height(panelHeight).
+		Padding(1, 2).
+		Render(m.vp.View())
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
+}
@@ -0,0 +1,150 @@
+package platform
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Facts describes the host go-pwr runs on, so scripts can be matched
+// against their Platforms, Distros, PackageManagers and Architectures tags.
+type Facts struct {
+	OS              string   // runtime.GOOS, e.g. "linux"
+	Arch            string   // runtime.GOARCH, e.g. "amd64"
+	Distro          string   // os-release ID, e.g. "ubuntu"; empty outside Linux
+	DistroLike      []string // os-release ID_LIKE, e.g. ["debian"]
+	DistroVersion   string   // os-release VERSION_ID, e.g. "24.04"
+	PackageManagers []string // Package managers found on PATH
+	WSL             bool     // Running under Windows Subsystem for Linux
+	Container       bool     // Running inside a container
+}
+
+// KnownPackageManagers are the package managers looked for on PATH.
+var KnownPackageManagers = []string{
+	"apt", "dnf", "yum", "zypper", "pacman", "yay", "paru", "apk", "emerge", "xbps-install", "nix",
+	"snap", "flatpak", "brew", "port", "winget", "choco", "scoop",
+}
+
+var (
+	hostFacts     Facts
+	hostFactsOnce sync.Once
+)
+
+// HostFacts returns the facts of the current host. They are collected once.
+func HostFacts() Facts {
+	hostFactsOnce.Do(func() {
+		hostFacts = DetectFacts()
+	})
+	return hostFacts
+}
+
+// DetectFacts collects the facts of the current host.
+func DetectFacts() Facts {
+	facts := Facts{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+	}
+
+	if IsLinux() {
+		release := readOSRelease()
+		facts.Distro = strings.ToLower(release["ID"])
+		facts.DistroLike = strings.Fields(strings.ToLower(release["ID_LIKE"]))
+		facts.DistroVersion = release["VERSION_ID"]
+		facts.WSL = detectWSL()
+		facts.Container = detectContainer()
+	}
+
+	for _, manager := range KnownPackageManagers {
+		if _, err := exec.LookPath(manager); err == nil {
+			facts.PackageManagers = append(facts.PackageManagers, manager)
+		}
+	}
+	sort.Strings(facts.PackageManagers)
+	return facts
+}
+
+// HasPackageManager reports whether the named package manager is installed.
+func (f Facts) HasPackageManager(name string) bool {
+	for _, manager := range f.PackageManagers {
+		if strings.EqualFold(manager, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Env returns the facts as GO_PWR_* environment variables for scripts.
+func (f Facts) Env() []string {
+	return []string{
+		"GO_PWR_OS=" + f.OS,
+		"GO_PWR_ARCH=" + f.Arch,
+		"GO_PWR_DISTRO=" + f.Distro,
+		"GO_PWR_DISTRO_LIKE=" + strings.Join(f.DistroLike, " "),
+		"GO_PWR_DISTRO_VERSION=" + f.DistroVersion,
+		"GO_PWR_PACKAGE_MANAGERS=" + strings.Join(f.PackageManagers, " "),
+		"GO_PWR_WSL=" + boolEnv(f.WSL),
+		"GO_PWR_CONTAINER=" + boolEnv(f.Container),
+	}
+}
+
+// boolEnv formats a flag as "1" or "0" for shell tests.
+func boolEnv(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// readOSRelease parses /etc/os-release (or /usr/lib/os-release).
+func readOSRelease() map[string]string {
+	values := make(map[string]string)
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+			if !ok || strings.HasPrefix(key, "#") {
+				continue
+			}
+			values[key] = strings.Trim(value, `"'`)
+		}
+		break
+	}
+	return values
+}
+
+// detectWSL reports whether Linux is running under WSL.
+func detectWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	data, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(data)), "microsoft")
+}
+
+// detectContainer reports whether we are inside a container.
+func detectContainer() bool {
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	if os.Getenv("container") != "" {
+		return true
+	}
+	data, err := os.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return false
+	}
+	cgroup := string(data)
+	return strings.Contains(cgroup, "docker") || strings.Contains(cgroup, "kubepods") || strings.Contains(cgroup, "containerd")
+}
@@ -0,0 +1,201 @@
+package platform
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Interpreter describes how to run one kind of script.
+type Interpreter struct {
+	Name       string   // Human-readable name, e.g. "Python"
+	Extensions []string // File extensions handled, including the dot
+	Command    []string // Program and leading arguments; the script path is appended
+	Language   string   // Syntax name or extension passed to bat; empty lets bat decide
+
+	LineComments []string  // Line comment markers, e.g. "#" or "REM"; empty means "#"
+	BlockComment [2]string // Block comment delimiters, e.g. {"<#", "#>"}; empty if none
+}
+
+// CommentMarkers returns the line comment markers of the interpreter's
+// language.
+func (i Interpreter) CommentMarkers() []string {
+	if len(i.LineComments) == 0 {
+		return []string{"#"}
+	}
+	return i.LineComments
+}
+
+// Program returns the executable the interpreter runs.
+func (i Interpreter) Program() string {
+	if len(i.Command) == 0 {
+		return ""
+	}
+	return i.Command[0]
+}
+
+// Available reports whether the interpreter's program is installed.
+func (i Interpreter) Available() bool {
+	if i.Program() == "" {
+		return false
+	}
+	_, err := exec.LookPath(i.Program())
+	return err == nil
+}
+
+// CommandLine returns the program and arguments that run scriptPath.
+func (i Interpreter) CommandLine(scriptPath string) []string {
+	args := make([]string, 0, len(i.Command)+1)
+	args = append(args, i.Command...)
+	return append(args, scriptPath)
+}
+
+// registry maps lower-case file extensions to interpreters.
+var registry = struct {
+	sync.RWMutex
+	byExt map[string]Interpreter
+}{byExt: make(map[string]Interpreter)}
+
+func init() {
+	for _, interp := range defaultInterpreters() {
+		RegisterInterpreter(interp)
+	}
+}
+
+// defaultInterpreters returns the interpreters go-pwr knows out of the box.
+func defaultInterpreters() []Interpreter {
+	python := "python3"
+	shell := []string{"bash"}
+	if IsWindows() {
+		python = "python" // The Windows installer does not provide python3
+		shell = []string{"bash", "-l"}
+	}
+
+	return []Interpreter{
+		{Name: "Bash", Extensions: []string{".sh", ".bash"}, Command: shell, Language: "bash"},
+		{Name: "Zsh", Extensions: []string{".zsh"}, Command: []string{"zsh"}, Language: "zsh"},
+		{Name: "Fish", Extensions: []string{".fish"}, Command: []string{"fish"}, Language: "fish"},
+		{Name: "PowerShell", Extensions: []string{".ps1"}, Command: []string{"pwsh", "-File"}, Language: "ps1",
+			BlockComment: [2]string{"<#", "#>"}},
+		{Name: "Batch", Extensions: []string{".bat", ".cmd"}, Command: []string{"cmd", "/C"}, Language: "bat",
+			LineComments: []string{"@REM", "REM", "::"}},
+		{Name: "Python", Extensions: []string{".py"}, Command: []string{python}, Language: "py"},
+		{Name: "Node.js", Extensions: []string{".js", ".mjs"}, Command: []string{"node"}, Language: "js",
+			LineComments: []string{"//"}, BlockComment: [2]string{"/*", "*/"}},
+		{Name: "Deno", Extensions: []string{".ts"}, Command: []string{"deno", "run", "--allow-all"}, Language: "ts",
+			LineComments: []string{"//"}, BlockComment: [2]string{"/*", "*/"}},
+		{Name: "Ruby", Extensions: []string{".rb"}, Command: []string{"ruby"}, Language: "rb",
+			BlockComment: [2]string{"=begin", "=end"}},
+		{Name: "Nushell", Extensions: []string{".nu"}, Command: []string{"nu"}},
+	}
+}
+
+// RegisterInterpreter adds an interpreter, replacing any existing
+// interpreter for the same extensions.
+func RegisterInterpreter(interp Interpreter) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, ext := range interp.Extensions {
+		registry.byExt[normalizeExt(ext)] = interp
+	}
+}
+
+// RegisterInterpreterCommands registers interpreters from configuration,
+// mapping extensions to command lines such as {".py": "python3 -u"}. An
+// extension that already has an interpreter keeps its name, language and
+// comment syntax, so only the command changes; e.g. a .ps1 override is
+// still launched the way PowerShell scripts are.
+func RegisterInterpreterCommands(commands map[string]string) error {
+	for ext, command := range commands {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			return fmt.Errorf("interpreter for %s has an empty command", ext)
+		}
+		interp, ok := InterpreterFor(normalizeExt(ext))
+		if !ok {
+			interp = Interpreter{Name: filepath.Base(fields[0])}
+		}
+		interp.Extensions = []string{ext}
+		interp.Command = fields
+		RegisterInterpreter(interp)
+	}
+	return nil
+}
+
+// InterpreterFor returns the interpreter registered for the file's extension.
+func InterpreterFor(path string) (Interpreter, bool) {
+	ext := normalizeExt(filepath.Ext(path))
+	if ext == "" {
+		return Interpreter{}, false
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	interp, ok := registry.byExt[ext]
+	return interp, ok
+}
+
+// IsSupportedScript reports whether an interpreter is registered for the
+// file's extension.
+func IsSupportedScript(name string) bool {
+	_, ok := InterpreterFor(name)
+	return ok
+}
+
+// SupportedExtensions returns all registered extensions, sorted.
+func SupportedExtensions() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	exts := make([]string, 0, len(registry.byExt))
+	for ext := range registry.byExt {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// normalizeExt lower-cases an extension and makes sure it starts with a dot.
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// shellQuote quotes a string for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes and joins a command line for POSIX shells.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// powerShellJoin quotes and joins a command line for PowerShell.
+func powerShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// windowsJoin quotes and joins a command line for cmd.exe.
+func windowsJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t&()^|<>") {
+			arg = `"` + arg + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 )
@@ -38,24 +39,76 @@ func GetOSName() string {
 	}
 }
 
+// RunOptions customizes how a script is run.
+type RunOptions struct {
+	Args []string // Arguments passed to the script
+	Env  []string // Extra environment variables, as "KEY=value"
+
+	// Elevate is the program, such as sudo or doas, that runs the script
+	// with administrator rights. Empty runs it as the current user.
+	Elevate string
+}
+
+// env returns the variables go-pwr adds for a script: the host facts as
+// GO_PWR_* variables followed by the extra environment.
+func (o RunOptions) env() []string {
+	return append(HostFacts().Env(), o.Env...)
+}
+
+// environ returns the environment for a script process.
+func (o RunOptions) environ() []string {
+	return append(os.Environ(), o.env()...)
+}
+
+// shellCommand returns a POSIX shell command that runs commandLine with the
+// extra environment. The variables are set inline because terminals and
+// tmux start the command from their own environment, not go-pwr's.
+func (o RunOptions) shellCommand(commandLine []string) string {
+	return shellJoin(o.wrap(commandLine))
+}
+
+// wrap prefixes commandLine with env, so the variables survive elevation
+// (sudo resets the environment), and with the elevation program if set.
+// Windows has neither, and passes the environment on as is.
+func (o RunOptions) wrap(commandLine []string) []string {
+	if IsWindows() {
+		return commandLine
+	}
+	wrapped := append(append([]string{"env"}, o.env()...), commandLine...)
+	if o.Elevate != "" {
+		wrapped = append([]string{o.Elevate}, wrapped...)
+	}
+	return wrapped
+}
+
 // ExecuteScript runs a script in a new terminal window based on the platform.
+// The script is run by the interpreter its shebang or extension selects.
 func ExecuteScript(scriptPath, scriptName string) error {
+	return ExecuteScriptWithOptions(scriptPath, scriptName, RunOptions{})
+}
+
+// ExecuteScriptWithOptions is like ExecuteScript but applies opts.
+func ExecuteScriptWithOptions(scriptPath, scriptName string, opts RunOptions) error {
+	interp, err := interpreterFor(scriptPath)
+	if err != nil {
+		return err
+	}
+	commandLine := append(interp.CommandLine(scriptPath), opts.Args...)
+
 	var cmd *exec.Cmd
 
 	if IsWindows() {
-		if strings.HasSuffix(scriptName, ".ps1") {
-			cmd = exec.Command("cmd", "/C", "start", "pwsh", "-NoExit", "-Command", "Clear-Host; "+scriptPath+"; Write-Host ''; Read-Host 'Press Enter to exit'")
+		if opts.Elevate != "" {
+			return fmt.Errorf("scripts cannot be elevated on Windows; start go-pwr from an Administrator terminal instead")
+		}
+		if interp.Name == "PowerShell" {
+			cmd = exec.Command("cmd", "/C", "start", interp.Program(), "-NoExit", "-Command", "Clear-Host; & "+powerShellJoin(append([]string{scriptPath}, opts.Args...))+"; Write-Host ''; Read-Host 'Press Enter to exit'")
 		} else {
-			cmd = exec.Command("cmd", "/C", "start", "cmd", "/K", "cls && bash -l "+scriptPath+" & pause")
+			cmd = exec.Command("cmd", "/C", "start", "cmd", "/K", "cls && "+windowsJoin(commandLine)+" & pause")
 		}
 	} else if IsMac() {
 		// Improved macOS terminal handling
-		scriptCmd := scriptPath
-		if strings.HasSuffix(scriptName, ".ps1") {
-			scriptCmd = "pwsh " + scriptPath
-		} else {
-			scriptCmd = "bash " + scriptPath
-		}
+		scriptCmd := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(opts.shellCommand(commandLine))
 		osaCmd := fmt.Sprintf(`tell application "Terminal"
     do script "clear; %s; echo; read -n 1 -s -r -p 'Press any key to exit...'"
     activate
@@ -65,7 +118,7 @@ end tell`, scriptCmd)
 		// Check if we're in a server/headless environment (no DISPLAY)
 		if !IsDesktopEnvironment() {
 			// Server environment: run in current terminal with tmux/screen if available
-			return ExecuteInCurrentTerminal(scriptPath, scriptName)
+			return executeInCurrentTerminal(scriptPath, scriptName, opts)
 		}
 
 		// Desktop environment: try common GUI terminals
@@ -78,19 +131,56 @@ end tell`, scriptCmd)
 		}
 		if term == "" {
 			// Fallback to current terminal execution if no GUI terminal found
-			return ExecuteInCurrentTerminal(scriptPath, scriptName)
+			return executeInCurrentTerminal(scriptPath, scriptName, opts)
 		}
 
-		if strings.HasSuffix(scriptName, ".ps1") {
-			cmd = exec.Command(term, "--", "bash", "-l", "-c", "clear; pwsh "+scriptPath+"; echo; read -p 'Press Enter to exit'")
-		} else {
-			cmd = exec.Command(term, "--", "bash", "-l", "-c", "clear; bash "+scriptPath+"; echo; read -p 'Press Enter to exit'")
-		}
+		cmd = exec.Command(term, "--", "bash", "-l", "-c", "clear; "+opts.shellCommand(commandLine)+"; echo; read -p 'Press Enter to exit'")
 	}
 
+	cmd.Env = opts.environ()
 	return cmd.Start()
 }
 
+// RunScript runs a script in the foreground, attached to the current
+// terminal, and returns when it exits.
+func RunScript(scriptPath string, opts RunOptions) error {
+	cmd, err := ScriptCommand(scriptPath, opts)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// ScriptCommand returns the command that runs a script in the foreground,
+// without its standard streams connected.
+func ScriptCommand(scriptPath string, opts RunOptions) (*exec.Cmd, error) {
+	interp, err := interpreterFor(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+	commandLine := opts.wrap(append(interp.CommandLine(scriptPath), opts.Args...))
+
+	cmd := exec.Command(commandLine[0], commandLine[1:]...)
+	cmd.Env = opts.environ()
+	return cmd, nil
+}
+
+// interpreterFor returns the interpreter for a script, failing if none is
+// found or its program is not installed.
+func interpreterFor(scriptPath string) (Interpreter, error) {
+	interp, ok := ResolveInterpreter(scriptPath)
+	if !ok {
+		return Interpreter{}, fmt.Errorf("no interpreter found for %s", filepath.Base(scriptPath))
+	}
+	if !interp.Available() {
+		return Interpreter{}, fmt.Errorf("%s is not installed (needed to run %s scripts)", interp.Program(), interp.Name)
+	}
+	return interp, nil
+}
+
 // IsDesktopEnvironment checks if we're running in a desktop environment
 func IsDesktopEnvironment() bool {
 	// Check for DISPLAY environment variable (X11)
@@ -117,31 +207,32 @@ func IsDesktopEnvironment() bool {
 
 // ExecuteInCurrentTerminal executes a script in the current terminal using tmux or direct execution
 func ExecuteInCurrentTerminal(scriptPath, scriptName string) error {
+	return executeInCurrentTerminal(scriptPath, scriptName, RunOptions{})
+}
+
+// executeInCurrentTerminal is ExecuteInCurrentTerminal with run options.
+func executeInCurrentTerminal(scriptPath, scriptName string, opts RunOptions) error {
+	interp, err := interpreterFor(scriptPath)
+	if err != nil {
+		return err
+	}
+	commandLine := append(interp.CommandLine(scriptPath), opts.Args...)
+	quotedPath := shellQuote(scriptPath)
+	preview := fmt.Sprintf("if command -v bat &>/dev/null; then bat --theme=\"DarkNeon\" --style=numbers --color=always %s; elif command -v batcat &>/dev/null; then batcat --theme=\"DarkNeon\" --style=numbers --color=always %s; else cat %s; fi", quotedPath, quotedPath, quotedPath)
+
 	// Check if we're already in tmux
 	if os.Getenv("TMUX") != "" {
 		// We're in tmux - create a new window
-		var cmd *exec.Cmd
-		if strings.HasSuffix(scriptName, ".ps1") {
-			cmd = exec.Command("tmux", "new-window", "-n", scriptName, "bash", "-c", 
-				fmt.Sprintf("clear; echo 'Running: %s'; if command -v bat &>/dev/null; then bat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; elif command -v batcat &>/dev/null; then batcat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; else cat '%s'; fi; echo; pwsh '%s'; echo; read -p 'Press Enter to close this window...'", scriptName, scriptPath, scriptPath, scriptPath, scriptPath))
-		} else {
-			cmd = exec.Command("tmux", "new-window", "-n", scriptName, "bash", "-c", 
-				fmt.Sprintf("clear; echo 'Running: %s'; if command -v bat &>/dev/null; then bat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; elif command -v batcat &>/dev/null; then batcat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; else cat '%s'; fi; echo; bash '%s'; echo; read -p 'Press Enter to close this window...'", scriptName, scriptPath, scriptPath, scriptPath, scriptPath))
-		}
+		cmd := exec.Command("tmux", "new-window", "-n", scriptName, "bash", "-c",
+			fmt.Sprintf("clear; echo 'Running: %s'; %s; echo; %s; echo; read -p 'Press Enter to close this window...'", scriptName, preview, opts.shellCommand(commandLine)))
 		return cmd.Start()
 	}
 
 	// Check if tmux is available and start a new session
 	if _, err := exec.LookPath("tmux"); err == nil {
-		var cmd *exec.Cmd
 		sessionName := fmt.Sprintf("go-pwr-%s", strings.ReplaceAll(scriptName, ".", "-"))
-		if strings.HasSuffix(scriptName, ".ps1") {
-			cmd = exec.Command("tmux", "new-session", "-d", "-s", sessionName, "bash", "-c", 
-				fmt.Sprintf("clear; echo 'Running: %s'; echo 'Use Ctrl+B then D to detach, or exit to close'; if command -v bat &>/dev/null; then bat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; elif command -v batcat &>/dev/null; then batcat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; else cat '%s'; fi; echo; pwsh '%s'; echo; read -p 'Press Enter to close this session...'", scriptName, scriptPath, scriptPath, scriptPath, scriptPath))
-		} else {
-			cmd = exec.Command("tmux", "new-session", "-d", "-s", sessionName, "bash", "-c", 
-				fmt.Sprintf("clear; echo 'Running: %s'; echo 'Use Ctrl+B then D to detach, or exit to close'; if command -v bat &>/dev/null; then bat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; elif command -v batcat &>/dev/null; then batcat --theme=\"DarkNeon\" --style=numbers --color=always '%s'; else cat '%s'; fi; echo; bash '%s'; echo; read -p 'Press Enter to close this session...'", scriptName, scriptPath, scriptPath, scriptPath, scriptPath))
-		}
+		cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "bash", "-c",
+			fmt.Sprintf("clear; echo 'Running: %s'; echo 'Use Ctrl+B then D to detach, or exit to close'; %s; echo; %s; echo; read -p 'Press Enter to close this session...'", scriptName, preview, opts.shellCommand(commandLine)))
 		if err := cmd.Start(); err == nil {
 			// Attach to the session
 			attachCmd := exec.Command("tmux", "attach-session", "-t", sessionName)
@@ -156,18 +247,15 @@ func ExecuteInCurrentTerminal(scriptPath, scriptName string) error {
 	fmt.Printf("Install tmux for better experience: sudo apt install tmux\n")
 	fmt.Printf("========================================================\n\n")
 
-	var cmd *exec.Cmd
-	if strings.HasSuffix(scriptName, ".ps1") {
-		cmd = exec.Command("pwsh", scriptPath)
-	} else {
-		cmd = exec.Command("bash", scriptPath)
-	}
+	wrapped := opts.wrap(commandLine)
+	cmd := exec.Command(wrapped[0], wrapped[1:]...)
+	cmd.Env = opts.environ()
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	err := cmd.Run()
+	err = cmd.Run()
 
 	fmt.Printf("\n========================================================\n")
 	fmt.Printf("Script execution completed. Press Enter to continue...")
@@ -0,0 +1,49 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ElevateAuto picks the first of sudo or doas that is installed.
+const ElevateAuto = "auto"
+
+// IsElevated reports whether go-pwr runs with administrator rights: as root
+// on Linux and macOS, or from an elevated prompt on Windows.
+func IsElevated() bool {
+	if IsWindows() {
+		// Only administrators may open the raw disk device
+		file, err := os.Open(`\\.\PHYSICALDRIVE0`)
+		if err != nil {
+			return false
+		}
+		file.Close()
+		return true
+	}
+	return os.Geteuid() == 0
+}
+
+// ElevationCommand returns the program used to run scripts with
+// administrator rights. preferred is "sudo", "doas" or another command, or
+// "auto" (or empty) to use whichever of sudo and doas is installed.
+func ElevationCommand(preferred string) (string, error) {
+	if IsWindows() {
+		return "", fmt.Errorf("scripts cannot be elevated on Windows; start go-pwr from an Administrator terminal instead")
+	}
+
+	candidates := []string{preferred}
+	if preferred == "" || preferred == ElevateAuto {
+		candidates = []string{"sudo", "doas"}
+	}
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	if len(candidates) > 1 {
+		return "", fmt.Errorf("neither sudo nor doas is installed; run go-pwr as root instead")
+	}
+	return "", fmt.Errorf("%s is not installed; change elevate_command in the config or run go-pwr as root", preferred)
+}
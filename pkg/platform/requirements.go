@@ -0,0 +1,297 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Requirement is something a script needs on the host, declared in its
+// Requires header field:
+//
+//	git          the git command on PATH
+//	git>=2.30    git, reporting version 2.30 or newer
+//	~/.ssh/id_ed25519, /etc/os-release
+//	             a file; anything with a path separator or starting with ~
+type Requirement struct {
+	Name       string // Command name, or path for files
+	MinVersion string // Oldest acceptable version, empty for any
+	File       bool   // Name is a path rather than a command
+}
+
+// Unmet is a requirement the host does not meet.
+type Unmet struct {
+	Requirement
+	Reason string // e.g. "git is not installed"
+}
+
+// versionProbeTimeout bounds each "--version" probe.
+const versionProbeTimeout = 3 * time.Second
+
+var (
+	versionPattern    = regexp.MustCompile(`\d+(?:\.\d+)+|\d+`)
+	minVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+	requirementMemo   sync.Map // Requirement spec to the reason it is unmet, "" if met
+)
+
+// Version requirements queued by CheckRequirementsQuickly until
+// ProbeRequirements runs them.
+var (
+	probeMu       sync.Mutex
+	pendingProbes = make(map[string]Requirement)
+	probesQueued  = make(chan struct{}, 1)
+)
+
+// ParseRequirement parses one entry of a Requires field.
+func ParseRequirement(spec string) (Requirement, error) {
+	spec = strings.TrimSpace(spec)
+	var req Requirement
+	if strings.HasPrefix(spec, "~") || strings.ContainsAny(spec, `/\`) {
+		req = Requirement{Name: spec, File: true}
+	} else if name, version, ok := strings.Cut(spec, ">="); ok {
+		req = Requirement{Name: name, MinVersion: version}
+		if !minVersionPattern.MatchString(version) {
+			return req, fmt.Errorf("invalid version %q in requirement %q; expected e.g. %s>=1.2", version, spec, name)
+		}
+	} else {
+		req = Requirement{Name: spec}
+	}
+	if req.Name == "" {
+		return req, fmt.Errorf("requirement %q names no command or file", spec)
+	}
+	return req, nil
+}
+
+// String returns the requirement as it is written in a header.
+func (r Requirement) String() string {
+	if r.MinVersion != "" {
+		return r.Name + ">=" + r.MinVersion
+	}
+	return r.Name
+}
+
+// Check returns why the requirement is not met on this host, or an empty
+// string if it is.
+func (r Requirement) Check() string {
+	if r.File {
+		path := os.ExpandEnv(r.Name)
+		if strings.HasPrefix(path, "~") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[1:])
+			}
+		}
+		if _, err := os.Stat(path); err != nil {
+			return r.Name + " does not exist"
+		}
+		return ""
+	}
+
+	program, err := exec.LookPath(r.Name)
+	if err != nil {
+		return r.Name + " is not installed"
+	}
+	if r.MinVersion == "" {
+		return ""
+	}
+	version := probeVersion(program)
+	if version == "" {
+		return fmt.Sprintf("could not tell which version of %s is installed (need %s)", r.Name, r.MinVersion)
+	}
+	if CompareVersions(version, r.MinVersion) < 0 {
+		return fmt.Sprintf("%s %s is older than %s", r.Name, version, r.MinVersion)
+	}
+	return ""
+}
+
+// probeVersion asks a program for its version, trying "--version" and then
+// "version" (as go and kubectl expect).
+func probeVersion(program string) string {
+	for _, arg := range []string{"--version", "version"} {
+		ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+		output, err := exec.CommandContext(ctx, program, arg).CombinedOutput()
+		cancel()
+		if err != nil {
+			continue
+		}
+		if version := versionPattern.FindString(string(output)); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// CompareVersions compares dotted version numbers, returning -1, 0 or 1.
+// Missing parts count as 0, so 2.3 equals 2.3.0.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckRequirements returns the requirements among specs that this host
+// does not meet, including malformed ones. Results are remembered until
+// ForgetRequirementChecks, so the same command is not probed twice.
+func CheckRequirements(specs []string) []Unmet {
+	var unmet []Unmet
+	for _, spec := range specs {
+		req, err := ParseRequirement(spec)
+		if err != nil {
+			unmet = append(unmet, Unmet{Requirement: req, Reason: err.Error()})
+			continue
+		}
+		reason, ok := requirementMemo.Load(spec)
+		if !ok {
+			reason = req.Check()
+			requirementMemo.Store(spec, reason)
+		}
+		if reason != "" {
+			unmet = append(unmet, Unmet{Requirement: req, Reason: reason.(string)})
+		}
+	}
+	return unmet
+}
+
+// CheckRequirementsQuickly is CheckRequirements without running anything,
+// for listings: a version requirement whose command is installed but not
+// probed yet counts as met and is queued for ProbeRequirements.
+func CheckRequirementsQuickly(specs []string) []Unmet {
+	var unmet []Unmet
+	for _, spec := range specs {
+		req, err := ParseRequirement(spec)
+		if err != nil {
+			unmet = append(unmet, Unmet{Requirement: req, Reason: err.Error()})
+			continue
+		}
+		reason, ok := requirementMemo.Load(spec)
+		if !ok && req.MinVersion != "" {
+			if _, err := exec.LookPath(req.Name); err == nil {
+				queueProbe(spec, req)
+				continue
+			}
+		}
+		if !ok {
+			reason = req.Check() // Runs no program: no minimum version, or not installed
+			requirementMemo.Store(spec, reason)
+		}
+		if reason != "" {
+			unmet = append(unmet, Unmet{Requirement: req, Reason: reason.(string)})
+		}
+	}
+	return unmet
+}
+
+// queueProbe queues a version requirement and signals RequirementProbesQueued.
+func queueProbe(spec string, req Requirement) {
+	probeMu.Lock()
+	pendingProbes[spec] = req
+	probeMu.Unlock()
+	select {
+	case probesQueued <- struct{}{}:
+	default: // Already signalled
+	}
+}
+
+// RequirementProbesQueued receives a value when CheckRequirementsQuickly has
+// queued version probes.
+func RequirementProbesQueued() <-chan struct{} {
+	return probesQueued
+}
+
+// ProbeRequirements runs the queued version probes and reports whether any
+// requirement turned out to be unmet, so listings made meanwhile are stale.
+func ProbeRequirements() bool {
+	probeMu.Lock()
+	pending := pendingProbes
+	pendingProbes = make(map[string]Requirement)
+	probeMu.Unlock()
+
+	unmet := false
+	for spec, req := range pending {
+		if _, ok := requirementMemo.Load(spec); ok {
+			continue // Checked at a launch meanwhile
+		}
+		reason := req.Check()
+		requirementMemo.Store(spec, reason)
+		unmet = unmet || reason != ""
+	}
+	return unmet
+}
+
+// ForgetRequirementChecks forgets the results for specs, e.g. before a
+// script is launched, since a missing command may have been installed
+// meanwhile.
+func ForgetRequirementChecks(specs []string) {
+	for _, spec := range specs {
+		requirementMemo.Delete(spec)
+	}
+}
+
+// installCommands are how each package manager installs a package.
+var installCommands = map[string]string{
+	"apt":          "sudo apt install %s",
+	"dnf":          "sudo dnf install %s",
+	"yum":          "sudo yum install %s",
+	"zypper":       "sudo zypper install %s",
+	"pacman":       "sudo pacman -S %s",
+	"yay":          "yay -S %s",
+	"paru":         "paru -S %s",
+	"apk":          "sudo apk add %s",
+	"emerge":       "sudo emerge %s",
+	"xbps-install": "sudo xbps-install %s",
+	"nix":          "nix-env -iA nixpkgs.%s",
+	"snap":         "sudo snap install %s",
+	"brew":         "brew install %s",
+	"port":         "sudo port install %s",
+	"winget":       "winget install %s",
+	"choco":        "choco install %s",
+	"scoop":        "scoop install %s",
+}
+
+// packageNames maps commands to the package that usually provides them,
+// where the two differ.
+var packageNames = map[string]string{
+	"rg":   "ripgrep",
+	"pwsh": "powershell",
+	"node": "nodejs",
+	"npm":  "nodejs",
+}
+
+// InstallHints suggests how to install the command a requirement names with
+// each package manager on this host. Files get no hints.
+func (r Requirement) InstallHints() []string {
+	if r.File {
+		return nil
+	}
+	pkg := r.Name
+	if name, ok := packageNames[pkg]; ok {
+		pkg = name
+	}
+	var hints []string
+	for _, manager := range HostFacts().PackageManagers {
+		if command, ok := installCommands[manager]; ok {
+			hints = append(hints, fmt.Sprintf(command, pkg))
+		}
+	}
+	return hints
+}
@@ -0,0 +1,119 @@
+package platform
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxShebangLength bounds how much of a file is read to find its shebang.
+const maxShebangLength = 256
+
+// ReadShebang returns the interpreter command line declared by the first
+// line of a file, e.g. ["python3"] for "#!/usr/bin/env python3".
+func ReadShebang(path string) ([]string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReaderSize(file, maxShebangLength).ReadSlice('\n')
+	if err != nil && len(line) == 0 {
+		return nil, false
+	}
+	return ParseShebang(string(line))
+}
+
+// ParseShebang parses a shebang line. A leading env is dropped along with
+// its options and variable assignments, so "#!/usr/bin/env -S deno run"
+// yields ["deno", "run"].
+func ParseShebang(line string) ([]string, bool) {
+	if !strings.HasPrefix(line, "#!") {
+		return nil, false
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	if filepath.Base(fields[0]) == "env" {
+		fields = fields[1:]
+		for len(fields) > 0 && (strings.HasPrefix(fields[0], "-") || strings.Contains(fields[0], "=")) {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			return nil, false
+		}
+	}
+	return fields, true
+}
+
+// shebangInterpreter builds an interpreter from a shebang command line,
+// borrowing the preview language of a registered interpreter for the same
+// program. Extensions are tried in sorted order, preferring one with a
+// language, so the same interpreter wins on every run.
+func shebangInterpreter(command []string) Interpreter {
+	program := filepath.Base(command[0])
+	if IsWindows() {
+		// Unix paths such as /bin/bash do not exist on Windows, so rely on PATH
+		command = append([]string{program}, command[1:]...)
+	}
+
+	interp := Interpreter{Name: program, Command: command}
+	var match *Interpreter
+	for _, ext := range SupportedExtensions() {
+		registered, ok := InterpreterFor(ext)
+		if !ok || filepath.Base(registered.Program()) != program {
+			continue
+		}
+		if match == nil || (match.Language == "" && registered.Language != "") {
+			match = &registered
+		}
+	}
+	if match != nil {
+		interp.Language = match.Language
+		interp.LineComments = match.LineComments
+		interp.BlockComment = match.BlockComment
+	}
+	return interp
+}
+
+// ResolveInterpreter returns the interpreter that runs a script. Outside
+// Windows a shebang line takes precedence over the file extension, since
+// that is what the kernel would honour; on Windows the shebang is only used
+// for files without a registered extension.
+func ResolveInterpreter(path string) (Interpreter, bool) {
+	command, hasShebang := ReadShebang(path)
+	if hasShebang && !IsWindows() {
+		return shebangInterpreter(command), true
+	}
+	if interp, ok := InterpreterFor(path); ok {
+		return interp, true
+	}
+	if hasShebang {
+		return shebangInterpreter(command), true
+	}
+	return Interpreter{}, false
+}
+
+// IsScriptPath reports whether a file should be listed as a script: either
+// its extension has a registered interpreter, or it has no extension but
+// declares a shebang (and, outside Windows, is executable).
+func IsScriptPath(path string) bool {
+	if IsSupportedScript(path) {
+		return true
+	}
+	if filepath.Ext(path) != "" {
+		return false
+	}
+	if !IsWindows() {
+		info, err := os.Stat(path)
+		if err != nil || info.Mode().Perm()&0111 == 0 {
+			return false
+		}
+	}
+	_, ok := ReadShebang(path)
+	return ok
+}